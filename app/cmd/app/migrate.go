@@ -0,0 +1,51 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+
+	"CommentTree/internal/storage/postgres"
+
+	"github.com/wb-go/wbf/config"
+)
+
+var ErrUsage = errors.New("usage: migrate up | migrate down [steps]")
+
+// migrate changes schema of postgres without starting server,
+// down reverts one migration when steps aren't set.
+func migrate(args []string) error {
+	if len(args) == 0 || len(args) > 2 {
+		return ErrUsage
+	}
+
+	// Migrations fill search_vector with language of config.
+	cfg := config.New()
+	if err := cfg.Load(ConfigPath); err != nil {
+		return err
+	}
+	opts := &postgres.Options{SearchLanguage: cfg.GetString("search.language")}
+
+	switch {
+	case args[0] == "up" && len(args) == 1:
+		p := postgres.New(ConnString, opts)
+		defer p.Shutdown()
+
+		return p.Migrate()
+	case args[0] == "down":
+		steps := 1
+		if len(args) == 2 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n <= 0 {
+				return ErrUsage
+			}
+			steps = n
+		}
+
+		p := postgres.New(ConnString, opts)
+		defer p.Shutdown()
+
+		return p.Rollback(steps)
+	}
+
+	return ErrUsage
+}
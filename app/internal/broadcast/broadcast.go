@@ -0,0 +1,137 @@
+package broadcast
+
+import (
+	"sync"
+
+	"CommentTree/internal/entities/event"
+)
+
+const (
+	// Events kept for resume of reconnected subscribers.
+	DefaultHistory = 256
+	// Events waiting in subscriber channel, slow subscriber is dropped.
+	SubscriberBuffer = 32
+)
+
+// Subscription gets events about replies to ParentID.
+type Subscription struct {
+	ParentID int64
+	// Closed when subscriber is dropped or unsubscribed.
+	C  <-chan event.Event
+	ch chan event.Event
+	// Missed events since Last-Event-ID, they go before C.
+	Replay []event.Event
+}
+
+// Broadcaster delivers events of this instance to subscribers.
+type Broadcaster struct {
+	mu      sync.Mutex
+	lastID  int64
+	history []event.Event
+	size    int
+	subs    map[*Subscription]struct{}
+	closed  bool
+}
+
+func New(history int) *Broadcaster {
+	if history <= 0 {
+		history = DefaultHistory
+	}
+
+	return &Broadcaster{
+		history: make([]event.Event, 0, history),
+		size:    history,
+		subs:    make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish sends e to subscribers of it's parent, e without id gets
+// the next one. Ids from other sources, like NOTIFY, are kept.
+func (b *Broadcaster) Publish(e event.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if e.ID == 0 {
+		b.lastID++
+		e.ID = b.lastID
+	} else if e.ID > b.lastID {
+		b.lastID = e.ID
+	}
+
+	if len(b.history) == b.size {
+		b.history = append(b.history[:0], b.history[1:]...)
+	}
+	b.history = append(b.history, e)
+
+	for s := range b.subs {
+		if s.ParentID != e.ParentID {
+			continue
+		}
+
+		select {
+		case s.ch <- e:
+		default:
+			// Client reconnects with Last-Event-ID and gets missed events.
+			b.drop(s)
+		}
+	}
+}
+
+// Subscribe returns subscription on replies to parentID,
+// with lastID > 0 it replays events after it which are still in history.
+func (b *Broadcaster) Subscribe(parentID, lastID int64) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan event.Event, SubscriberBuffer)
+	s := &Subscription{
+		ParentID: parentID,
+		C:        ch,
+		ch:       ch,
+	}
+
+	if lastID > 0 {
+		for _, e := range b.history {
+			if e.ID > lastID && e.ParentID == parentID {
+				s.Replay = append(s.Replay, e)
+			}
+		}
+	}
+
+	// After shutdown subscription is closed at once.
+	if b.closed {
+		close(ch)
+		return s
+	}
+	b.subs[s] = struct{}{}
+
+	return s
+}
+
+func (b *Broadcaster) Unsubscribe(s *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.drop(s)
+}
+
+// drop closes subscription, b.mu should be locked.
+func (b *Broadcaster) drop(s *Subscription) {
+	if _, ok := b.subs[s]; !ok {
+		return
+	}
+
+	delete(b.subs, s)
+	close(s.ch)
+}
+
+// Shutdown closes all subscriptions, so streams are finished.
+func (b *Broadcaster) Shutdown() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for s := range b.subs {
+		b.drop(s)
+	}
+}
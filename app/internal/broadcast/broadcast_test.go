@@ -0,0 +1,89 @@
+package broadcast
+
+import (
+	"testing"
+
+	"CommentTree/internal/entities/event"
+)
+
+func TestBroadcaster_Publish(t *testing.T) {
+	b := New(10)
+	sub := b.Subscribe(1, 0)
+	other := b.Subscribe(2, 0)
+
+	b.Publish(event.Event{Type: event.TypeCreated, CommentID: 5, ParentID: 1})
+
+	select {
+	case e := <-sub.C:
+		if e.ID != 1 || e.CommentID != 5 {
+			t.Errorf("Publish() got = %v", e)
+		}
+	default:
+		t.Fatal("Publish() event not delivered")
+	}
+
+	select {
+	case e := <-other.C:
+		t.Errorf("Publish() event of other parent delivered: %v", e)
+	default:
+	}
+}
+
+func TestBroadcaster_Replay(t *testing.T) {
+	b := New(2)
+	for i := int64(1); i <= 3; i++ {
+		b.Publish(event.Event{Type: event.TypeCreated, CommentID: i, ParentID: 1})
+	}
+	b.Publish(event.Event{Type: event.TypeCreated, CommentID: 9, ParentID: 2})
+
+	// First event is out of history, event of other parent is skipped.
+	sub := b.Subscribe(1, 1)
+	if len(sub.Replay) != 1 || sub.Replay[0].CommentID != 3 {
+		t.Errorf("Subscribe() replay = %v, want only comment 3", sub.Replay)
+	}
+}
+
+func TestBroadcaster_SlowSubscriber(t *testing.T) {
+	b := New(10)
+	sub := b.Subscribe(1, 0)
+
+	for i := 0; i <= SubscriberBuffer; i++ {
+		b.Publish(event.Event{Type: event.TypeCreated, ParentID: 1})
+	}
+
+	n := 0
+	for range sub.C {
+		n++
+	}
+	if n != SubscriberBuffer {
+		t.Errorf("slow subscriber got %v events, want %v", n, SubscriberBuffer)
+	}
+}
+
+func TestBroadcaster_Shutdown(t *testing.T) {
+	b := New(10)
+	sub := b.Subscribe(1, 0)
+	b.Shutdown()
+
+	if _, ok := <-sub.C; ok {
+		t.Error("Shutdown() subscription isn't closed")
+	}
+	if _, ok := <-b.Subscribe(1, 0).C; ok {
+		t.Error("Subscribe() after Shutdown() isn't closed")
+	}
+}
+
+func TestBroadcaster_KeepID(t *testing.T) {
+	b := New(10)
+	sub := b.Subscribe(1, 0)
+
+	b.Publish(event.Event{ID: 40, Type: event.TypeCreated, ParentID: 1})
+	b.Publish(event.Event{Type: event.TypeCreated, ParentID: 1})
+
+	if e := <-sub.C; e.ID != 40 {
+		t.Errorf("Publish() id = %v, want 40", e.ID)
+	}
+	if e := <-sub.C; e.ID != 41 {
+		t.Errorf("Publish() id = %v, want 41", e.ID)
+	}
+}
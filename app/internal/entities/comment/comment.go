@@ -1,20 +1,118 @@
 package comment
 
+import "time"
+
 const (
 	// Elements per page for pagination.
 	PageElements = 10
+
+	// Max length of identifier of external resource.
+	MaxThreadKeyLen = 128
+
+	// Sort orders of comments.
+	SortOldest      = "oldest"
+	SortNewest      = "newest"
+	SortMostReplied = "most_replied"
+	// By score of votes.
+	SortTop = "top"
+
+	// Moderation statuses, only approved comments are public.
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+
+	// Search modes, SearchSubstr if empty.
+	SearchSubstr   = "substr"
+	SearchFulltext = "fulltext"
+	// Case-insensitive substring or trigram similarity.
+	SearchFuzzy = "fuzzy"
+
+	// Tree loading limits.
+	DefaultTreeDepth = 3
+	MaxTreeDepth     = 10
+	MaxTreeLimit     = 50
+
+	// Message of deleted comment in responses.
+	DeletedMessage = "[deleted]"
 )
 
 type Comment struct {
 	ID       int64
 	Message  string
 	ParentID int64
+	// Deprecated: lists have NextCursor of CommentView instead, flag is
+	// kept for compatibility with old clients. In trees it's still set
+	// on the last loaded reply of node which has more replies.
 	HaveNext bool `json:"have_next"`
+	Deleted  bool `json:"deleted"`
+	// ID of user who deleted comment, author can restore
+	// only own deletion, not deletion of moderator.
+	DeletedBy int64 `json:"deleted_by,omitempty"`
+	// ID of user who wrote comment, 0 for anonymous.
+	AuthorID int64 `json:"author_id,omitempty"`
+	// Resource which tree belongs to, replies inherit it from root.
+	ThreadKey string `json:"thread_key"`
+	// One of Status constants.
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	// Changed on edit, delete and restore.
+	UpdatedAt time.Time `json:"updated_at"`
+	// Time of last edit, nil if comment wasn't edited.
+	EditedAt *time.Time `json:"edited_at,omitempty"`
+	// Count of direct replies.
+	ReplyCount int64 `json:"reply_count"`
+	// Count of replies on all levels below.
+	DescendantCount int64 `json:"descendant_count"`
+	// Upvotes minus downvotes.
+	Score int64 `json:"score"`
+	// Count of every set reaction, votes included.
+	Reactions map[string]int64 `json:"reactions,omitempty"`
+	// Relevance and highlighted text, only for fulltext search.
+	Rank    float64 `json:"rank,omitempty"`
+	Snippet string  `json:"snippet,omitempty"`
+	// Closeness to searched text from 0 to 1, only for fuzzy search.
+	Similarity float64 `json:"similarity,omitempty"`
+}
+
+// Previous version of comment message.
+type Revision struct {
+	ID        int64     `json:"id"`
+	CommentID int64     `json:"comment_id"`
+	Message   string    `json:"message"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type CommentView struct {
 	Parent Comment   `json:"parent"`
 	Childs []Comment `json:"childs"`
+	// Token for loading next page with after, empty on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// Single comment with it's reply count and position in the tree.
+type SingleView struct {
+	Comment
+	// Count of ancestors, 0 for root comments.
+	Depth int64 `json:"depth"`
+	// ID of the root comment of the thread, equal to ID for roots.
+	RootID int64 `json:"root_id"`
+}
+
+// Comment with it's loaded replies, HaveNext of the last reply
+// shows that node have more replies than was loaded.
+type TreeNode struct {
+	Comment
+	Replies []*TreeNode `json:"replies"`
+}
+
+type TreeOpts struct {
+	// Levels of replies under the root node.
+	MaxDepth int
+	// Replies per one node.
+	Limit int
+	// Moderators load tree of not approved comment,
+	// replies are approved ones anyway.
+	AnyStatus bool
 }
 
 type GetterOpts struct {
@@ -23,10 +121,51 @@ type GetterOpts struct {
 
 	Page   int
 	Substr string
+	// Only comments of this thread, all threads if empty.
+	ThreadKey string
+	// Only comments with this status, StatusApproved if empty.
+	Status string
+	// Moderators see comments in every status, Status is ignored.
+	AnyStatus bool
+	// One of Sort constants, SortOldest if empty.
+	Sort string
+	// One of Search constants, fulltext and fuzzy results are
+	// ordered by rank and similarity.
+	SearchMode string
+	// Keyset pagination, when set Page is ignored.
+	After *Cursor
+}
+
+// Public reports that comment is seen by readers, not only by moderators.
+func (c *Comment) Public() bool {
+	return c.Status == StatusApproved
+}
+
+func ValidSort(sort string) bool {
+	switch sort {
+	case "", SortOldest, SortNewest, SortMostReplied, SortTop:
+		return true
+	}
+
+	return false
+}
+
+func ValidSearchMode(mode string) bool {
+	switch mode {
+	case "", SearchSubstr, SearchFulltext, SearchFuzzy:
+		return true
+	}
+
+	return false
+}
+
+// RankedSearch reports that results of mode are ordered by score.
+func RankedSearch(mode string) bool {
+	return mode == SearchFulltext || mode == SearchFuzzy
 }
 
 func (g *GetterOpts) Empty() bool {
-	if g.Page == 0 && g.Substr == "" {
+	if g.Page == 0 && g.Substr == "" && g.After == nil {
 		return true
 	}
 
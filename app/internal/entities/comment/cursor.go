@@ -0,0 +1,59 @@
+package comment
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var (
+	ErrWrongCursor = errors.New("wrong cursor")
+)
+
+// Cursor points to the last comment of loaded page,
+// next page starts right after it in order of Sort.
+type Cursor struct {
+	Sort      string    `json:"s,omitempty"`
+	CreatedAt time.Time `json:"t"`
+	// Value of computed sort column, like reply count.
+	Rank int64 `json:"r,omitempty"`
+	ID   int64 `json:"i"`
+}
+
+func NewCursor(c Comment, sort string) Cursor {
+	cur := Cursor{
+		Sort:      sort,
+		CreatedAt: c.CreatedAt,
+		ID:        c.ID,
+	}
+	switch sort {
+	case SortMostReplied:
+		cur.Rank = c.ReplyCount
+	case SortTop:
+		cur.Rank = c.Score
+	}
+
+	return cur
+}
+
+// Encode returns opaque token for clients.
+func (c Cursor) Encode() string {
+	// Marshal of two plain fields can't fail.
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func DecodeCursor(token string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, ErrWrongCursor
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID <= 0 {
+		return Cursor{}, ErrWrongCursor
+	}
+
+	return c, nil
+}
@@ -0,0 +1,114 @@
+package comment
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCursor_Encode(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		c    Cursor
+	}{
+		{
+			name: "good",
+			c: Cursor{
+				CreatedAt: time.Date(2025, 1, 2, 3, 4, 5, 6000, time.UTC),
+				ID:        12,
+			},
+		},
+		{
+			name: "with rank",
+			c: Cursor{
+				Sort: SortMostReplied,
+				Rank: 5,
+				ID:   12,
+			},
+		},
+		{
+			name: "zero time",
+			c: Cursor{
+				ID: 1,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeCursor(tt.c.Encode())
+			if err != nil {
+				t.Fatalf("DecodeCursor() err = %v", err)
+			}
+			if !got.CreatedAt.Equal(tt.c.CreatedAt) || got.ID != tt.c.ID ||
+				got.Sort != tt.c.Sort || got.Rank != tt.c.Rank {
+				t.Errorf("DecodeCursor() = %v, want %v", got, tt.c)
+			}
+		})
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+	tests := []struct {
+		name  string // description of this test case
+		token string
+		want  error
+	}{
+		{
+			name:  "not base64",
+			token: "%%%",
+			want:  ErrWrongCursor,
+		},
+		{
+			name:  "not json",
+			token: "YXNkZg",
+			want:  ErrWrongCursor,
+		},
+		{
+			name:  "without id",
+			token: "e30",
+			want:  ErrWrongCursor,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeCursor(tt.token)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("DecodeCursor() err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewCursor(t *testing.T) {
+	c := Comment{ID: 3, ReplyCount: 7, Score: -2}
+
+	tests := []struct {
+		name string // description of this test case
+		sort string
+		want int64
+	}{
+		{
+			name: "oldest",
+			sort: SortOldest,
+			want: 0,
+		},
+		{
+			name: "most replied",
+			sort: SortMostReplied,
+			want: 7,
+		},
+		{
+			name: "top",
+			sort: SortTop,
+			want: -2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewCursor(c, tt.sort)
+			if got.Rank != tt.want || got.Sort != tt.sort || got.ID != c.ID {
+				t.Errorf("NewCursor() = %v, want rank %v", got, tt.want)
+			}
+		})
+	}
+}
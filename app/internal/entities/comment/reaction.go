@@ -0,0 +1,35 @@
+package comment
+
+const (
+	// Votes, user can have only one of them on comment.
+	ReactionUp   = "up"
+	ReactionDown = "down"
+)
+
+// Emoji reactions, user can set any of them besides vote.
+var Emojis = []string{"👍", "❤️", "😂", "😮", "😢", "🎉"}
+
+func ValidReaction(kind string) bool {
+	if kind == ReactionUp || kind == ReactionDown {
+		return true
+	}
+	for _, e := range Emojis {
+		if kind == e {
+			return true
+		}
+	}
+
+	return false
+}
+
+// OppositeVote returns vote replaced by kind, empty for emoji.
+func OppositeVote(kind string) string {
+	switch kind {
+	case ReactionUp:
+		return ReactionDown
+	case ReactionDown:
+		return ReactionUp
+	}
+
+	return ""
+}
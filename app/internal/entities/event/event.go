@@ -0,0 +1,23 @@
+package event
+
+import "CommentTree/internal/entities/comment"
+
+const (
+	// Types of events.
+	TypeCreated = "created"
+	TypeDeleted = "deleted"
+	TypeEdited  = "edited"
+)
+
+// Event tells subscribers of parent about change of it's reply.
+type Event struct {
+	// Set by broadcaster or notifier, increases with every event.
+	ID        int64            `json:"id"`
+	Type      string           `json:"type"`
+	CommentID int64            `json:"comment_id"`
+	ParentID  int64            `json:"parent_id"`
+	Comment   *comment.Comment `json:"comment,omitempty"`
+	// Deleted comment is removed with all replies, events about
+	// replies aren't sent, so clients drop the subtree themselves.
+	Cascade bool `json:"cascade,omitempty"`
+}
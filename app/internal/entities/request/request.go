@@ -1,8 +1,26 @@
 package request
 
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"CommentTree/internal/entities/comment"
+	"CommentTree/internal/entities/event"
+	"CommentTree/internal/entities/webhook"
+)
+
+const (
+	MaxNameLen = 64
+	// Min length of webhook secret set by client.
+	MinSecretLen = 16
+)
+
 type CreateComment struct {
 	Message  string `json:"message"`
 	ParentID int64  `json:"parent_id"`
+	// Required for roots, replies take it from parent.
+	ThreadKey string `json:"thread_key"`
 }
 
 func (cc *CreateComment) Validate() string {
@@ -12,6 +30,48 @@ func (cc *CreateComment) Validate() string {
 	if cc.ParentID < 0 {
 		return "wrong parent id"
 	}
+	if cc.ParentID == 0 && cc.ThreadKey == "" {
+		return "empty thread_key, it's required for root comment"
+	}
+	if len(cc.ThreadKey) > comment.MaxThreadKeyLen {
+		return fmt.Sprintf(
+			"too long thread_key, max length is %d", comment.MaxThreadKeyLen,
+		)
+	}
+
+	return ""
+}
+
+type EditComment struct {
+	Message string `json:"message"`
+}
+
+func (ec *EditComment) Validate() string {
+	if ec.Message == "" {
+		return "empty message"
+	}
+
+	return ""
+}
+
+type DeleteComment struct {
+	// Removes replies too, it's allowed to moderators only.
+	Cascade bool `form:"cascade"`
+}
+
+type Reaction struct {
+	// Vote or emoji, in query for delete.
+	Kind string `json:"kind" form:"kind"`
+}
+
+func (r *Reaction) Validate() string {
+	if !comment.ValidReaction(r.Kind) {
+		return fmt.Sprintf(
+			"wrong kind, kind should be %s, %s or one of: %s",
+			comment.ReactionUp, comment.ReactionDown,
+			strings.Join(comment.Emojis, " "),
+		)
+	}
 
 	return ""
 }
@@ -21,6 +81,13 @@ type GetComments struct {
 	Substr       string `form:"substr"`
 	Page         int    `form:"page"`
 	SearchGlobal bool   `form:"search_global"`
+	ThreadKey    string `form:"thread_key"`
+	Sort         string `form:"sort"`
+	SearchMode   string `form:"search_mode"`
+	After        string `form:"after"`
+
+	// Decoded After, set by Validate.
+	Cursor *comment.Cursor `form:"-"`
 }
 
 func (gc *GetComments) Validate() string {
@@ -35,5 +102,205 @@ func (gc *GetComments) Validate() string {
 		gc.Page++
 	}
 
+	if !comment.ValidSort(gc.Sort) {
+		return fmt.Sprintf(
+			"wrong sort, sort should be one of: %s, %s, %s, %s",
+			comment.SortOldest, comment.SortNewest,
+			comment.SortMostReplied, comment.SortTop,
+		)
+	}
+
+	if !comment.ValidSearchMode(gc.SearchMode) {
+		return fmt.Sprintf(
+			"wrong search_mode, search_mode should be one of: %s, %s, %s",
+			comment.SearchSubstr, comment.SearchFulltext, comment.SearchFuzzy,
+		)
+	}
+
+	if comment.RankedSearch(gc.SearchMode) {
+		if gc.Substr == "" {
+			return fmt.Sprintf("empty substr for %s search", gc.SearchMode)
+		}
+		// Results are ordered by relevance and paged by page.
+		if gc.Sort != "" || gc.After != "" {
+			return fmt.Sprintf(
+				"%s search can't be used with sort or after", gc.SearchMode,
+			)
+		}
+	}
+
+	if gc.After != "" {
+		c, err := comment.DecodeCursor(gc.After)
+		if err != nil {
+			return "wrong after, should be next_cursor from previous page"
+		}
+		// Cursor of other order points to wrong place.
+		if c.Sort != gc.Sort {
+			return "wrong after, cursor was made for other sort"
+		}
+		gc.Cursor = &c
+	}
+
+	return ""
+}
+
+type Stream struct {
+	ParentID int64 `form:"parent"`
+	// For clients which can't set Last-Event-ID header.
+	LastEventID int64 `form:"last_event_id"`
+}
+
+func (s *Stream) Validate() string {
+	if s.ParentID < 0 {
+		return "wrong parent, parent should be >= 0"
+	}
+	if s.LastEventID < 0 {
+		return "wrong last event id, it should be >= 0"
+	}
+
+	return ""
+}
+
+// Types of messages from websocket client.
+const (
+	WSSubscribe   = "subscribe"
+	WSUnsubscribe = "unsubscribe"
+	WSCreate      = "create"
+	WSEdit        = "edit"
+	WSDelete      = "delete"
+)
+
+// WSMessage is message from websocket client, fields are used by Type.
+type WSMessage struct {
+	Type string `json:"type"`
+	// Sent back in reply, so client can match them.
+	RequestID string `json:"request_id"`
+	// For subscribe, unsubscribe and create.
+	ParentID int64 `json:"parent_id"`
+	// For subscribe, events after it are replayed.
+	LastEventID int64 `json:"last_event_id"`
+	// For edit and delete.
+	ID int64 `json:"id"`
+	// For create and edit.
+	Message   string `json:"message"`
+	ThreadKey string `json:"thread_key"`
+	Cascade   bool   `json:"cascade"`
+}
+
+func (m *WSMessage) Validate() string {
+	switch m.Type {
+	case WSSubscribe, WSUnsubscribe:
+		s := Stream{ParentID: m.ParentID, LastEventID: m.LastEventID}
+		return s.Validate()
+	case WSCreate:
+		cc := CreateComment{
+			Message:   m.Message,
+			ParentID:  m.ParentID,
+			ThreadKey: m.ThreadKey,
+		}
+		return cc.Validate()
+	case WSEdit:
+		if m.ID <= 0 {
+			return "wrong id, id should be > 0"
+		}
+		ec := EditComment{Message: m.Message}
+		return ec.Validate()
+	case WSDelete:
+		if m.ID <= 0 {
+			return "wrong id, id should be > 0"
+		}
+		return ""
+	}
+
+	return fmt.Sprintf(
+		"wrong type, type should be one of: %s, %s, %s, %s, %s",
+		WSSubscribe, WSUnsubscribe, WSCreate, WSEdit, WSDelete,
+	)
+}
+
+type CreateWebhook struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	// Generated when empty.
+	Secret string `json:"secret"`
+}
+
+func (cw *CreateWebhook) Validate() string {
+	u, err := url.Parse(cw.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "wrong url, it should be absolute http or https url"
+	}
+	if len(cw.URL) > webhook.MaxURLLen {
+		return fmt.Sprintf("too long url, max length is %d", webhook.MaxURLLen)
+	}
+
+	if len(cw.Events) == 0 {
+		return "empty events"
+	}
+	for _, e := range cw.Events {
+		if !webhook.ValidEvent(e) {
+			return fmt.Sprintf(
+				"wrong event %q, events should be: %s, %s",
+				e, event.TypeCreated, event.TypeDeleted,
+			)
+		}
+	}
+
+	if cw.Secret != "" && len(cw.Secret) < MinSecretLen {
+		return fmt.Sprintf("too short secret, min length is %d", MinSecretLen)
+	}
+
+	return ""
+}
+
+type ModerationQueue struct {
+	Page int `form:"page"`
+}
+
+func (mq *ModerationQueue) Validate() string {
+	if mq.Page < 0 {
+		return "wrong page, page should be >= 0"
+	}
+
+	if mq.Page == 0 {
+		mq.Page++
+	}
+
+	return ""
+}
+
+type Register struct {
+	Name string `json:"name"`
+}
+
+func (r *Register) Validate() string {
+	if r.Name == "" {
+		return "empty name"
+	}
+	if len(r.Name) > MaxNameLen {
+		return fmt.Sprintf("too long name, max length is %d", MaxNameLen)
+	}
+
+	return ""
+}
+
+type GetTree struct {
+	MaxDepth int `form:"max_depth"`
+	Limit    int `form:"limit"`
+}
+
+func (gt *GetTree) Validate() string {
+	if gt.MaxDepth < 0 || gt.MaxDepth > comment.MaxTreeDepth {
+		return fmt.Sprintf(
+			"wrong max_depth, max_depth should be in [0, %d]",
+			comment.MaxTreeDepth,
+		)
+	}
+	if gt.Limit < 0 || gt.Limit > comment.MaxTreeLimit {
+		return fmt.Sprintf(
+			"wrong limit, limit should be in [0, %d]", comment.MaxTreeLimit,
+		)
+	}
+
 	return ""
 }
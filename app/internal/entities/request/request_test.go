@@ -1,22 +1,46 @@
 package request
 
 import (
+	"strings"
 	"testing"
+
+	"CommentTree/internal/entities/comment"
 )
 
 func TestCreateComment_Validate(t *testing.T) {
 	tests := []struct {
-		name     string // description of this test case
-		message  string
-		parentID int64
-		want     bool
+		name      string // description of this test case
+		message   string
+		parentID  int64
+		threadKey string
+		want      bool
 	}{
 		{
-			name:     "good",
+			name:      "good",
+			message:   "hi",
+			parentID:  0,
+			threadKey: "article-1",
+			want:      false,
+		},
+		{
+			name:     "reply without thread key",
 			message:  "hi",
-			parentID: 0,
+			parentID: 3,
 			want:     false,
 		},
+		{
+			name:     "root without thread key",
+			message:  "hi",
+			parentID: 0,
+			want:     true,
+		},
+		{
+			name:      "too long thread key",
+			message:   "hi",
+			parentID:  0,
+			threadKey: strings.Repeat("a", comment.MaxThreadKeyLen+1),
+			want:      true,
+		},
 		{
 			name:     "bad message",
 			message:  "",
@@ -35,6 +59,7 @@ func TestCreateComment_Validate(t *testing.T) {
 			var cc CreateComment
 			cc.Message = tt.message
 			cc.ParentID = tt.parentID
+			cc.ThreadKey = tt.threadKey
 			got := cc.Validate()
 			if tt.want && got == "" {
 				t.Errorf("Validate() = %v, want %T", got, tt.want)
@@ -51,6 +76,9 @@ func TestGetComments_Validate(t *testing.T) {
 		parentID int64
 		page     int
 		substr   string
+		sort     string
+		mode     string
+		after    string
 		want     bool
 	}{
 		{
@@ -69,6 +97,68 @@ func TestGetComments_Validate(t *testing.T) {
 			page:     -100,
 			want:     true,
 		},
+		{
+			name:  "good cursor",
+			after: comment.Cursor{ID: 1}.Encode(),
+			want:  false,
+		},
+		{
+			name:  "bad cursor",
+			after: "asdf",
+			want:  true,
+		},
+		{
+			name: "good sort",
+			sort: comment.SortNewest,
+			want: false,
+		},
+		{
+			name: "bad sort",
+			sort: "random",
+			want: true,
+		},
+		{
+			name:   "fulltext",
+			substr: "hello world",
+			mode:   comment.SearchFulltext,
+			want:   false,
+		},
+		{
+			name: "fulltext without substr",
+			mode: comment.SearchFulltext,
+			want: true,
+		},
+		{
+			name:   "fulltext with sort",
+			substr: "hello",
+			mode:   comment.SearchFulltext,
+			sort:   comment.SortNewest,
+			want:   true,
+		},
+		{
+			name:   "fuzzy",
+			substr: "Hello",
+			mode:   comment.SearchFuzzy,
+			want:   false,
+		},
+		{
+			name:   "fuzzy with after",
+			substr: "Hello",
+			mode:   comment.SearchFuzzy,
+			after:  comment.Cursor{ID: 1}.Encode(),
+			want:   true,
+		},
+		{
+			name: "bad search mode",
+			mode: "regexp",
+			want: true,
+		},
+		{
+			name:  "cursor of other sort",
+			sort:  comment.SortNewest,
+			after: comment.Cursor{ID: 1}.Encode(),
+			want:  true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -76,6 +166,9 @@ func TestGetComments_Validate(t *testing.T) {
 			gc.Substr = tt.substr
 			gc.ParentID = tt.parentID
 			gc.Page = tt.page
+			gc.Sort = tt.sort
+			gc.SearchMode = tt.mode
+			gc.After = tt.after
 			got := gc.Validate()
 			if tt.want && got == "" {
 				t.Errorf("Validate() = %v, want %T", got, tt.want)
@@ -85,3 +178,302 @@ func TestGetComments_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestGetTree_Validate(t *testing.T) {
+	tests := []struct {
+		name     string // description of this test case
+		maxDepth int
+		limit    int
+		want     bool
+	}{
+		{
+			name: "good",
+			want: false,
+		},
+		{
+			name:     "bad max depth",
+			maxDepth: -1,
+			want:     true,
+		},
+		{
+			name:     "too big max depth",
+			maxDepth: 1000,
+			want:     true,
+		},
+		{
+			name:  "too big limit",
+			limit: 1000,
+			want:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gt GetTree
+			gt.MaxDepth = tt.maxDepth
+			gt.Limit = tt.limit
+			got := gt.Validate()
+			if tt.want && got == "" {
+				t.Errorf("Validate() = %v, want %T", got, tt.want)
+			} else if !tt.want && got != "" {
+				t.Errorf("Validate() = %v, want %T", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEditComment_Validate(t *testing.T) {
+	tests := []struct {
+		name    string // description of this test case
+		message string
+		want    bool
+	}{
+		{
+			name:    "good",
+			message: "hi",
+			want:    false,
+		},
+		{
+			name:    "bad message",
+			message: "",
+			want:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ec EditComment
+			ec.Message = tt.message
+			got := ec.Validate()
+			if tt.want && got == "" {
+				t.Errorf("Validate() = %v, want %T", got, tt.want)
+			} else if !tt.want && got != "" {
+				t.Errorf("Validate() = %v, want %T", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegister_Validate(t *testing.T) {
+	tests := []struct {
+		name     string // description of this test case
+		userName string
+		want     bool
+	}{
+		{
+			name:     "good",
+			userName: "bob",
+			want:     false,
+		},
+		{
+			name:     "empty name",
+			userName: "",
+			want:     true,
+		},
+		{
+			name:     "too long name",
+			userName: string(make([]byte, MaxNameLen+1)),
+			want:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r Register
+			r.Name = tt.userName
+			got := r.Validate()
+			if tt.want && got == "" {
+				t.Errorf("Validate() = %v, want %T", got, tt.want)
+			} else if !tt.want && got != "" {
+				t.Errorf("Validate() = %v, want %T", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReaction_Validate(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		kind string
+		want bool
+	}{
+		{
+			name: "vote",
+			kind: comment.ReactionDown,
+			want: false,
+		},
+		{
+			name: "emoji",
+			kind: comment.Emojis[1],
+			want: false,
+		},
+		{
+			name: "empty kind",
+			kind: "",
+			want: true,
+		},
+		{
+			name: "unknown kind",
+			kind: "like",
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r Reaction
+			r.Kind = tt.kind
+			got := r.Validate()
+			if tt.want && got == "" {
+				t.Errorf("Validate() = %v, want %T", got, tt.want)
+			} else if !tt.want && got != "" {
+				t.Errorf("Validate() = %v, want %T", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStream_Validate(t *testing.T) {
+	tests := []struct {
+		name        string // description of this test case
+		parentID    int64
+		lastEventID int64
+		want        bool
+	}{
+		{
+			name:        "good",
+			parentID:    3,
+			lastEventID: 10,
+			want:        false,
+		},
+		{
+			name:     "bad parent id",
+			parentID: -1,
+			want:     true,
+		},
+		{
+			name:        "bad last event id",
+			lastEventID: -1,
+			want:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s Stream
+			s.ParentID = tt.parentID
+			s.LastEventID = tt.lastEventID
+			got := s.Validate()
+			if tt.want && got == "" {
+				t.Errorf("Validate() = %v, want %T", got, tt.want)
+			} else if !tt.want && got != "" {
+				t.Errorf("Validate() = %v, want %T", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWSMessage_Validate(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		m    WSMessage
+		want bool
+	}{
+		{
+			name: "subscribe",
+			m:    WSMessage{Type: WSSubscribe, ParentID: 3},
+			want: false,
+		},
+		{
+			name: "subscribe bad parent",
+			m:    WSMessage{Type: WSSubscribe, ParentID: -1},
+			want: true,
+		},
+		{
+			name: "create root without thread key",
+			m:    WSMessage{Type: WSCreate, Message: "hi"},
+			want: true,
+		},
+		{
+			name: "edit",
+			m:    WSMessage{Type: WSEdit, ID: 3, Message: "hi"},
+			want: false,
+		},
+		{
+			name: "delete without id",
+			m:    WSMessage{Type: WSDelete},
+			want: true,
+		},
+		{
+			name: "unknown type",
+			m:    WSMessage{Type: "ping"},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.m.Validate()
+			if tt.want && got == "" {
+				t.Errorf("Validate() = %v, want %T", got, tt.want)
+			} else if !tt.want && got != "" {
+				t.Errorf("Validate() = %v, want %T", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateWebhook_Validate(t *testing.T) {
+	tests := []struct {
+		name   string // description of this test case
+		url    string
+		events []string
+		secret string
+		want   bool
+	}{
+		{
+			name:   "good",
+			url:    "https://example.com/hook",
+			events: []string{"created", "deleted"},
+			want:   false,
+		},
+		{
+			name:   "relative url",
+			url:    "/hook",
+			events: []string{"created"},
+			want:   true,
+		},
+		{
+			name:   "not http url",
+			url:    "ftp://example.com/hook",
+			events: []string{"created"},
+			want:   true,
+		},
+		{
+			name: "empty events",
+			url:  "https://example.com/hook",
+			want: true,
+		},
+		{
+			name:   "unknown event",
+			url:    "https://example.com/hook",
+			events: []string{"edited"},
+			want:   true,
+		},
+		{
+			name:   "short secret",
+			url:    "https://example.com/hook",
+			events: []string{"created"},
+			secret: "123",
+			want:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cw CreateWebhook
+			cw.URL = tt.url
+			cw.Events = tt.events
+			cw.Secret = tt.secret
+			got := cw.Validate()
+			if tt.want && got == "" {
+				t.Errorf("Validate() = %v, want %T", got, tt.want)
+			} else if !tt.want && got != "" {
+				t.Errorf("Validate() = %v, want %T", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,8 +1,14 @@
 package response
 
+import "CommentTree/internal/entities/event"
+
 const (
 	StatusOK    = "ok"
 	StatusError = "error"
+
+	// Types of messages to websocket client.
+	WSEvent = "event"
+	WSReply = "reply"
 )
 
 type Response struct {
@@ -30,3 +36,29 @@ func Result(result any) Response {
 		Result: result,
 	}
 }
+
+// WSMessage is message to websocket client, reply on message
+// of client or event of subscription.
+type WSMessage struct {
+	Type string `json:"type"`
+	// RequestID of client message, only for replies.
+	RequestID string       `json:"request_id,omitempty"`
+	Event     *event.Event `json:"event,omitempty"`
+	Response
+}
+
+func WSReplyTo(requestID string, r Response) WSMessage {
+	return WSMessage{
+		Type:      WSReply,
+		RequestID: requestID,
+		Response:  r,
+	}
+}
+
+func WSEventOf(e event.Event) WSMessage {
+	return WSMessage{
+		Type:     WSEvent,
+		Event:    &e,
+		Response: OK(),
+	}
+}
@@ -0,0 +1,17 @@
+package user
+
+const (
+	// Roles of users.
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+)
+
+type User struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+func (u *User) IsModerator() bool {
+	return u.Role == RoleModerator
+}
@@ -0,0 +1,60 @@
+package webhook
+
+import (
+	"time"
+
+	"CommentTree/internal/entities/event"
+)
+
+const (
+	// Headers of webhook request.
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderSignature = "X-Webhook-Signature"
+	// Prefix of signature, it's hex of HMAC-SHA256 of body.
+	SignaturePrefix = "sha256="
+
+	// Max length of target url.
+	MaxURLLen = 2048
+)
+
+// Webhook is registration of service which receives comment events.
+type Webhook struct {
+	ID  int64  `json:"id"`
+	URL string `json:"url"`
+	// Key of signature, it's shown only on creation.
+	Secret string `json:"secret,omitempty"`
+	// Types of events which are sent, see event types.
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Payload is body of webhook request.
+type Payload struct {
+	Event     string `json:"event"`
+	CommentID int64  `json:"comment_id"`
+	// 0 for root comments.
+	ParentID  int64  `json:"parent_id"`
+	ThreadKey string `json:"thread_key"`
+	// Absent for anonymous comments.
+	AuthorID int64 `json:"author_id,omitempty"`
+	// Only in created event.
+	Message    string    `json:"message,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// Delivery is event from outbox which waits for sending to webhook.
+type Delivery struct {
+	ID        int64
+	WebhookID int64
+	URL       string
+	Secret    string
+	Event     string
+	Payload   []byte
+	// Sending tries, this one included.
+	Attempts int
+}
+
+func ValidEvent(e string) bool {
+	return e == event.TypeCreated || e == event.TypeDeleted
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"CommentTree/internal/entities/comment"
+	"CommentTree/internal/entities/event"
+	"CommentTree/internal/entities/user"
 	"CommentTree/internal/storage"
 )
 
@@ -17,6 +19,21 @@ func (s *Service) CreateComment(c comment.Comment) (int64, error) {
 	if c.ParentID < 0 {
 		return 0, fmt.Errorf("%w: %s", ErrWrongData, "wrong parent id")
 	}
+	if c.ParentID == 0 && c.ThreadKey == "" {
+		return 0, fmt.Errorf("%w: %s", ErrWrongData, "empty thread key for root")
+	}
+
+	message, flagged, err := s.applyFilters(c.Message)
+	if err != nil {
+		return 0, err
+	}
+	c.Message = message
+
+	// Flagged comment waits for moderator even without premoderation.
+	c.Status = comment.StatusApproved
+	if s.moderation == ModerationPre || flagged {
+		c.Status = comment.StatusPending
+	}
 
 	id, err := s.str.CreateComment(c)
 	if errors.Is(err, storage.ErrWrongForeignKey) {
@@ -25,9 +42,40 @@ func (s *Service) CreateComment(c comment.Comment) (int64, error) {
 		return 0, fmt.Errorf("%s: (%w)%w", op, ErrStorageInternal, err)
 	}
 
+	// Readers see comment only after approve.
+	if c.Status == comment.StatusApproved {
+		c.ID = id
+		s.publish(event.TypeCreated, c)
+	}
+
 	return id, nil
 }
 
+// canModify checks that u is author of comment or moderator.
+func (s *Service) canModify(u user.User, id int64) error {
+	const op = "internal.service.canModify"
+
+	if u.IsModerator() {
+		return nil
+	}
+
+	c, err := s.str.Get(id)
+	if errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf(
+			"%w: %s", ErrNotAffected, "not find comment with this id",
+		)
+	} else if err != nil {
+		return fmt.Errorf("%s: (%w)%w", op, ErrStorageInternal, err)
+	}
+
+	// Anonymous comments can be changed only by moderators.
+	if c.AuthorID == 0 || c.AuthorID != u.ID {
+		return fmt.Errorf("%w: %s", ErrForbidden, "you are not author of comment")
+	}
+
+	return nil
+}
+
 func (s *Service) Comments(parentID int64, opts *comment.GetterOpts) (*comment.CommentView, error) {
 	const op = "internal.service.comments"
 
@@ -46,17 +94,236 @@ func (s *Service) Comments(parentID int64, opts *comment.GetterOpts) (*comment.C
 	return result, nil
 }
 
-func (s *Service) DeleteComment(id int64) error {
+// Comment returns comment with id, only moderators see it before approve.
+func (s *Service) Comment(u user.User, id int64) (*comment.SingleView, error) {
+	const op = "internal.service.comment"
+
+	if id <= 0 {
+		return nil, fmt.Errorf("%w: %s", ErrWrongData, "wrong id")
+	}
+
+	result, err := s.str.Comment(id, u.IsModerator())
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: (%w)%w", op, ErrStorageInternal, err)
+	}
+
+	return result, nil
+}
+
+func (s *Service) Tree(u user.User, id int64, opts *comment.TreeOpts) (*comment.TreeNode, error) {
+	const op = "internal.service.tree"
+
+	if id <= 0 {
+		return nil, fmt.Errorf("%w: %s", ErrWrongData, "wrong id")
+	}
+
+	if opts == nil {
+		opts = &comment.TreeOpts{}
+	}
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = comment.DefaultTreeDepth
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = comment.PageElements
+	}
+	if opts.MaxDepth > comment.MaxTreeDepth || opts.Limit > comment.MaxTreeLimit {
+		return nil, fmt.Errorf("%w: %s", ErrWrongData, "too big tree")
+	}
+	opts.AnyStatus = u.IsModerator()
+
+	result, err := s.str.Tree(id, opts)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: (%w)%w", op, ErrStorageInternal, err)
+	}
+
+	return result, nil
+}
+
+// Path returns chain of comments from the root to comment with id.
+func (s *Service) Path(u user.User, id int64) ([]comment.Comment, error) {
+	const op = "internal.service.path"
+
+	if id <= 0 {
+		return nil, fmt.Errorf("%w: %s", ErrWrongData, "wrong id")
+	}
+
+	result, err := s.str.Path(id, u.IsModerator())
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: (%w)%w", op, ErrStorageInternal, err)
+	}
+
+	return result, nil
+}
+
+func (s *Service) EditComment(u user.User, id int64, message string) error {
+	const op = "internal.service.Edit"
+
+	if id <= 0 {
+		return fmt.Errorf("%w: %s", ErrWrongData, "wrong id")
+	}
+	if message == "" {
+		return fmt.Errorf("%w: %s", ErrWrongData, "empty comment text")
+	}
+
+	if err := s.canModify(u, id); err != nil {
+		return err
+	}
+
+	// Edit passes the same filters as new comment, so approved
+	// comment can't be changed to spam.
+	message, flagged, err := s.applyFilters(message)
+	if err != nil {
+		return err
+	}
+
+	var status string
+	if s.moderation == ModerationPre || flagged {
+		status = comment.StatusPending
+	}
+
+	err = s.str.EditComment(id, message, status)
+	if errors.Is(err, storage.ErrNotAffected) {
+		return fmt.Errorf(
+			"%w: %s", ErrNotAffected, "not find comment with this id",
+		)
+	} else if err != nil {
+		return fmt.Errorf("%s: (%w)%w", op, ErrStorageInternal, err)
+	}
+
+	if s.pub == nil {
+		return nil
+	}
+
+	// Comment is already changed, so error isn't returned to client.
+	c, err := s.str.Get(id)
+	if err == nil && c.Status == comment.StatusApproved {
+		s.publish(event.TypeEdited, *c)
+	}
+
+	return nil
+}
+
+func (s *Service) Revisions(u user.User, id int64) ([]comment.Revision, error) {
+	const op = "internal.service.revisions"
+
+	if id <= 0 {
+		return nil, fmt.Errorf("%w: %s", ErrWrongData, "wrong id")
+	}
+
+	result, err := s.str.Revisions(id, u.IsModerator())
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: (%w)%w", op, ErrStorageInternal, err)
+	}
+
+	return result, nil
+}
+
+// DeleteComment leaves tombstone on the place of comment,
+// with cascade comment removed with all replies. Replies are
+// of other users, so only moderator deletes with cascade.
+func (s *Service) DeleteComment(u user.User, id int64, cascade bool) error {
 	const op = "internal.service.Delete"
 
 	if id <= 0 {
 		return fmt.Errorf("%w: %s", ErrWrongData, "wrong id")
 	}
 
-	err := s.str.DeleteComment(id)
+	if cascade && !u.IsModerator() {
+		return fmt.Errorf("%w: %s", ErrForbidden, "only moderator deletes replies")
+	}
+
+	if err := s.canModify(u, id); err != nil {
+		return err
+	}
+
+	// Event is routed by parent, which is unknown after hard delete.
+	var deleted comment.Comment
+	if s.pub != nil {
+		c, err := s.str.Get(id)
+		if errors.Is(err, storage.ErrNotFound) {
+			return fmt.Errorf(
+				"%w: %s", ErrNotAffected, "not find comment with this id",
+			)
+		} else if err != nil {
+			return fmt.Errorf("%s: (%w)%w", op, ErrStorageInternal, err)
+		}
+		deleted = *c
+	}
+
+	err := s.str.DeleteComment(id, u.ID, cascade)
+	if errors.Is(err, storage.ErrNotAffected) {
+		return fmt.Errorf(
+			"%w: %s", ErrNotAffected, "not find comment with this id",
+		)
+	} else if err != nil {
+		return fmt.Errorf("%s: (%w)%w", op, ErrStorageInternal, err)
+	}
+
+	// Readers never got not approved comment, so they aren't told
+	// about it's deletion.
+	if s.pub != nil && deleted.Public() {
+		s.pub.Publish(event.Event{
+			Type:      event.TypeDeleted,
+			CommentID: deleted.ID,
+			ParentID:  deleted.ParentID,
+			Cascade:   cascade,
+		})
+	}
+
+	return nil
+}
+
+// canRestore checks that u is moderator or author who deleted comment,
+// so author can't undo deletion made by moderator.
+func (s *Service) canRestore(u user.User, id int64) error {
+	const op = "internal.service.canRestore"
+
+	if u.IsModerator() {
+		return nil
+	}
+
+	c, err := s.str.Get(id)
+	if errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf(
+			"%w: %s", ErrNotAffected, "not find comment with this id",
+		)
+	} else if err != nil {
+		return fmt.Errorf("%s: (%w)%w", op, ErrStorageInternal, err)
+	}
+
+	if c.AuthorID == 0 || c.AuthorID != u.ID {
+		return fmt.Errorf("%w: %s", ErrForbidden, "you are not author of comment")
+	}
+	if c.Deleted && c.DeletedBy != u.ID {
+		return fmt.Errorf("%w: %s", ErrForbidden, "comment was deleted by moderator")
+	}
+
+	return nil
+}
+
+func (s *Service) RestoreComment(u user.User, id int64) error {
+	const op = "internal.service.Restore"
+
+	if id <= 0 {
+		return fmt.Errorf("%w: %s", ErrWrongData, "wrong id")
+	}
+
+	if err := s.canRestore(u, id); err != nil {
+		return err
+	}
+
+	err := s.str.RestoreComment(id)
 	if errors.Is(err, storage.ErrNotAffected) {
 		return fmt.Errorf(
-			"%w: %s", ErrWrongData, "not find comment with this id",
+			"%w: %s", ErrNotAffected, "not find deleted comment with this id",
 		)
 	} else if err != nil {
 		return fmt.Errorf("%s: (%w)%w", op, ErrStorageInternal, err)
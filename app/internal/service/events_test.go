@@ -0,0 +1,189 @@
+package service
+
+import (
+	"testing"
+
+	"CommentTree/internal/entities/comment"
+	"CommentTree/internal/entities/event"
+)
+
+type PublisherMock struct {
+	events []event.Event
+}
+
+func (pm *PublisherMock) Publish(e event.Event) {
+	pm.events = append(pm.events, e)
+}
+
+func TestService_PublishCreate(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for receiver constructor.
+		mode string
+
+		want int
+	}{
+		{
+			name: "published",
+			mode: ModerationPost,
+			want: 1,
+		},
+		{
+			name: "pending isn't published",
+			mode: ModerationPre,
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pub := &PublisherMock{}
+			s := New(&StorageMock{
+				createF: func(c comment.Comment) (int64, error) {
+					return 7, nil
+				},
+			}, WithModeration(tt.mode), WithPublisher(pub))
+
+			_, err := s.CreateComment(comment.Comment{Message: "hi", ParentID: 3})
+			if err != nil {
+				t.Fatalf("Create() err = %v", err)
+			}
+			if len(pub.events) != tt.want {
+				t.Fatalf("Create() events = %v, want %v", len(pub.events), tt.want)
+			}
+			if tt.want > 0 && (pub.events[0].CommentID != 7 || pub.events[0].ParentID != 3) {
+				t.Errorf("Create() event = %v", pub.events[0])
+			}
+		})
+	}
+}
+
+func TestService_PublishDelete(t *testing.T) {
+	tests := []struct {
+		name    string // description of this test case
+		status  string
+		cascade bool
+		want    int
+	}{
+		{
+			name:   "published",
+			status: comment.StatusApproved,
+			want:   1,
+		},
+		{
+			name:    "cascade",
+			status:  comment.StatusApproved,
+			cascade: true,
+			want:    1,
+		},
+		{
+			name:   "pending isn't published",
+			status: comment.StatusPending,
+			want:   0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pub := &PublisherMock{}
+			s := New(&StorageMock{
+				getOneF: func(id int64) (*comment.Comment, error) {
+					return &comment.Comment{ID: id, ParentID: 3, Status: tt.status}, nil
+				},
+				deleteF: func(id, userID int64, cascade bool) error {
+					return nil
+				},
+			}, WithPublisher(pub))
+
+			if err := s.DeleteComment(moderator, 7, tt.cascade); err != nil {
+				t.Fatalf("Delete() err = %v", err)
+			}
+			if len(pub.events) != tt.want {
+				t.Fatalf("Delete() events = %v, want %v", len(pub.events), tt.want)
+			}
+			if tt.want > 0 && (pub.events[0].Type != event.TypeDeleted ||
+				pub.events[0].ParentID != 3 || pub.events[0].Cascade != tt.cascade) {
+				t.Errorf("Delete() event = %v", pub.events[0])
+			}
+		})
+	}
+}
+
+func TestService_PublishEdit(t *testing.T) {
+	tests := []struct {
+		name   string // description of this test case
+		status string
+		want   int
+	}{
+		{
+			name:   "published",
+			status: comment.StatusApproved,
+			want:   1,
+		},
+		{
+			name:   "pending isn't published",
+			status: comment.StatusPending,
+			want:   0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pub := &PublisherMock{}
+			s := New(&StorageMock{
+				getOneF: func(id int64) (*comment.Comment, error) {
+					return &comment.Comment{ID: id, ParentID: 3, Status: tt.status}, nil
+				},
+				editF: func(id int64, message, status string) error {
+					return nil
+				},
+			}, WithPublisher(pub))
+
+			if err := s.EditComment(moderator, 7, "hi"); err != nil {
+				t.Fatalf("Edit() err = %v", err)
+			}
+			if len(pub.events) != tt.want {
+				t.Fatalf("Edit() events = %v, want %v", len(pub.events), tt.want)
+			}
+			if tt.want > 0 && pub.events[0].Type != event.TypeEdited {
+				t.Errorf("Edit() event = %v", pub.events[0])
+			}
+		})
+	}
+}
+
+func TestService_PublishApprove(t *testing.T) {
+	tests := []struct {
+		name    string // description of this test case
+		created bool
+		want    string
+	}{
+		{
+			name:    "first approve",
+			created: true,
+			want:    event.TypeCreated,
+		},
+		{
+			name:    "approve after edit",
+			created: false,
+			want:    event.TypeEdited,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pub := &PublisherMock{}
+			s := New(&StorageMock{
+				getOneF: func(id int64) (*comment.Comment, error) {
+					return &comment.Comment{ID: id, ParentID: 3, Status: comment.StatusApproved}, nil
+				},
+				statusF: func(id int64, status string) (bool, error) {
+					return tt.created, nil
+				},
+			}, WithPublisher(pub))
+
+			if err := s.ApproveComment(moderator, 7); err != nil {
+				t.Fatalf("Approve() err = %v", err)
+			}
+			if len(pub.events) != 1 || pub.events[0].Type != tt.want {
+				t.Errorf("Approve() events = %v, want %v", pub.events, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// Actions of filter on bad content.
+	ActionReject = "reject"
+	ActionFlag   = "flag"
+	ActionMask   = "mask"
+
+	// Replacement of masked links.
+	MaskedLink = "[link removed]"
+)
+
+var (
+	ErrRejected = errors.New("comment rejected")
+
+	linkRegexp = regexp.MustCompile(`(?i)(?:https?://|www\.)\S+`)
+)
+
+// Verdict of filter, empty Action means message is clean.
+type Verdict struct {
+	Action string
+	Reason string
+	// Message after masking, set only with ActionMask.
+	Message string
+}
+
+// Filter checks message of new or edited comment.
+type Filter interface {
+	Check(message string) Verdict
+}
+
+// BannedWords finds words from list in any case.
+type BannedWords struct {
+	Action string
+	Words  []string
+}
+
+func (bw *BannedWords) Check(message string) Verdict {
+	banned := make(map[string]struct{}, len(bw.Words))
+	for _, w := range bw.Words {
+		banned[strings.ToLower(w)] = struct{}{}
+	}
+
+	found := false
+	masked := []rune(message)
+	runes := []rune(message)
+	for start := 0; start < len(runes); {
+		if !isWordRune(runes[start]) {
+			start++
+			continue
+		}
+
+		end := start
+		for end < len(runes) && isWordRune(runes[end]) {
+			end++
+		}
+		if _, ok := banned[strings.ToLower(string(runes[start:end]))]; ok {
+			found = true
+			for i := start; i < end; i++ {
+				masked[i] = '*'
+			}
+		}
+		start = end
+	}
+
+	if !found {
+		return Verdict{}
+	}
+
+	return Verdict{
+		Action:  bw.Action,
+		Reason:  "message contains banned words",
+		Message: string(masked),
+	}
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// LinkLimit allows no more than Max links in message.
+type LinkLimit struct {
+	Action string
+	Max    int
+}
+
+func (ll *LinkLimit) Check(message string) Verdict {
+	links := linkRegexp.FindAllStringIndex(message, -1)
+	if len(links) <= ll.Max {
+		return Verdict{}
+	}
+
+	// Links within limit stay, others are removed.
+	n := 0
+	masked := linkRegexp.ReplaceAllStringFunc(message, func(link string) string {
+		n++
+		if n <= ll.Max {
+			return link
+		}
+		return MaskedLink
+	})
+
+	return Verdict{
+		Action:  ll.Action,
+		Reason:  fmt.Sprintf("too many links, max is %d", ll.Max),
+		Message: masked,
+	}
+}
+
+// MaxLength limits length of message in symbols.
+type MaxLength struct {
+	Action string
+	Max    int
+}
+
+func (ml *MaxLength) Check(message string) Verdict {
+	if utf8.RuneCountInString(message) <= ml.Max {
+		return Verdict{}
+	}
+
+	return Verdict{
+		Action:  ml.Action,
+		Reason:  fmt.Sprintf("too long message, max length is %d", ml.Max),
+		Message: string([]rune(message)[:ml.Max]),
+	}
+}
+
+// RepeatedChars catches spam like "!!!!!!!!" or "aaaaaaaa",
+// where one symbol goes more than Max times in a row.
+type RepeatedChars struct {
+	Action string
+	Max    int
+}
+
+func (rc *RepeatedChars) Check(message string) Verdict {
+	var b strings.Builder
+	found := false
+
+	var prev rune
+	count := 0
+	for _, r := range message {
+		if r == prev {
+			count++
+		} else {
+			prev = r
+			count = 1
+		}
+
+		if count > rc.Max {
+			found = true
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	if !found {
+		return Verdict{}
+	}
+
+	return Verdict{
+		Action:  rc.Action,
+		Reason:  fmt.Sprintf("symbol repeats more than %d times in a row", rc.Max),
+		Message: b.String(),
+	}
+}
+
+// applyFilters runs message through all filters, it returns message
+// after masking and flag when comment should go to moderators.
+func (s *Service) applyFilters(message string) (string, bool, error) {
+	flagged := false
+	for _, f := range s.filters {
+		v := f.Check(message)
+		switch v.Action {
+		case ActionReject:
+			return "", false, fmt.Errorf("%w: %s", ErrRejected, v.Reason)
+		case ActionFlag:
+			flagged = true
+		case ActionMask:
+			message = v.Message
+		}
+	}
+
+	return message, flagged, nil
+}
+
+type FilterConfig struct {
+	// Filter is off when action is empty.
+	Action string `mapstructure:"action"`
+	Max    int    `mapstructure:"max"`
+}
+
+type FiltersConfig struct {
+	BannedWords struct {
+		Action string   `mapstructure:"action"`
+		Words  []string `mapstructure:"words"`
+	} `mapstructure:"banned_words"`
+	Links         FilterConfig `mapstructure:"links"`
+	MaxLength     FilterConfig `mapstructure:"max_length"`
+	RepeatedChars FilterConfig `mapstructure:"repeated_chars"`
+}
+
+// NewFilters creates chain of enabled filters from config.
+func NewFilters(cfg FiltersConfig) ([]Filter, error) {
+	filters := []Filter{}
+
+	add := func(name, action string, f Filter) error {
+		switch action {
+		case "":
+			return nil
+		case ActionReject, ActionFlag, ActionMask:
+			filters = append(filters, f)
+			return nil
+		}
+
+		return fmt.Errorf("unknown action %q of filter %s", action, name)
+	}
+
+	// Length goes first, so other filters don't check huge messages.
+	err := errors.Join(
+		add("max_length", cfg.MaxLength.Action, &MaxLength{
+			Action: cfg.MaxLength.Action, Max: cfg.MaxLength.Max,
+		}),
+		add("banned_words", cfg.BannedWords.Action, &BannedWords{
+			Action: cfg.BannedWords.Action, Words: cfg.BannedWords.Words,
+		}),
+		add("links", cfg.Links.Action, &LinkLimit{
+			Action: cfg.Links.Action, Max: cfg.Links.Max,
+		}),
+		add("repeated_chars", cfg.RepeatedChars.Action, &RepeatedChars{
+			Action: cfg.RepeatedChars.Action, Max: cfg.RepeatedChars.Max,
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return filters, nil
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"CommentTree/internal/entities/comment"
+)
+
+func TestFilters_Check(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		f       Filter
+		message string
+
+		wantAction  string
+		wantMessage string
+	}{
+		{
+			name:       "clean",
+			f:          &BannedWords{Action: ActionReject, Words: []string{"spam"}},
+			message:    "hello world",
+			wantAction: "",
+		},
+		{
+			name:        "banned word in other case",
+			f:           &BannedWords{Action: ActionMask, Words: []string{"spam"}},
+			message:     "buy SPAM, not spammer",
+			wantAction:  ActionMask,
+			wantMessage: "buy ****, not spammer",
+		},
+		{
+			name:        "too many links",
+			f:           &LinkLimit{Action: ActionMask, Max: 1},
+			message:     "see https://a.io and www.b.io",
+			wantAction:  ActionMask,
+			wantMessage: "see https://a.io and " + MaskedLink,
+		},
+		{
+			name:       "links in limit",
+			f:          &LinkLimit{Action: ActionFlag, Max: 2},
+			message:    "see https://a.io and www.b.io",
+			wantAction: "",
+		},
+		{
+			name:        "too long",
+			f:           &MaxLength{Action: ActionMask, Max: 3},
+			message:     "привет",
+			wantAction:  ActionMask,
+			wantMessage: "при",
+		},
+		{
+			name:        "repeated chars",
+			f:           &RepeatedChars{Action: ActionMask, Max: 2},
+			message:     "nooooo!!!",
+			wantAction:  ActionMask,
+			wantMessage: "noo!!",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.f.Check(tt.message)
+			if got.Action != tt.wantAction {
+				t.Fatalf("Check() action = %v, want %v", got.Action, tt.wantAction)
+			}
+			if tt.wantAction == ActionMask && got.Message != tt.wantMessage {
+				t.Errorf("Check() message = %q, want %q", got.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestService_CreateFiltered(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for receiver constructor.
+		filters []Filter
+		// Named input parameters for target function.
+		message string
+
+		want        error
+		wantMessage string
+		wantStatus  string
+	}{
+		{
+			name:        "masked",
+			filters:     []Filter{&BannedWords{Action: ActionMask, Words: []string{"bad"}}},
+			message:     "so bad",
+			wantMessage: "so ***",
+			wantStatus:  comment.StatusApproved,
+		},
+		{
+			name:        "flagged",
+			filters:     []Filter{&LinkLimit{Action: ActionFlag, Max: 0}},
+			message:     "www.a.io",
+			wantMessage: "www.a.io",
+			wantStatus:  comment.StatusPending,
+		},
+		{
+			name:    "rejected",
+			filters: []Filter{&MaxLength{Action: ActionReject, Max: 2}},
+			message: "long",
+			want:    ErrRejected,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got comment.Comment
+			s := New(&StorageMock{
+				createF: func(c comment.Comment) (int64, error) {
+					got = c
+					return 1, nil
+				},
+			}, WithFilters(tt.filters...))
+
+			_, gotErr := s.CreateComment(comment.Comment{
+				Message: tt.message, ThreadKey: "article-1",
+			})
+			if !errors.Is(gotErr, tt.want) {
+				t.Fatalf("Create() want = %v, get %v", tt.want, gotErr)
+			}
+			if tt.want != nil {
+				return
+			}
+			if got.Message != tt.wantMessage || got.Status != tt.wantStatus {
+				t.Errorf(
+					"Create() stored = %q %v, want %q %v",
+					got.Message, got.Status, tt.wantMessage, tt.wantStatus,
+				)
+			}
+		})
+	}
+}
+
+func TestService_EditFiltered(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for receiver constructor.
+		filters    []Filter
+		moderation string
+		// Named input parameters for target function.
+		message string
+
+		want        error
+		wantMessage string
+		wantStatus  string
+	}{
+		{
+			name:        "clean keeps status",
+			filters:     []Filter{&LinkLimit{Action: ActionFlag, Max: 0}},
+			message:     "fine",
+			wantMessage: "fine",
+			wantStatus:  "",
+		},
+		{
+			name:        "masked",
+			filters:     []Filter{&BannedWords{Action: ActionMask, Words: []string{"bad"}}},
+			message:     "so bad",
+			wantMessage: "so ***",
+			wantStatus:  "",
+		},
+		{
+			name:        "flagged goes to moderation",
+			filters:     []Filter{&LinkLimit{Action: ActionFlag, Max: 0}},
+			message:     "www.a.io",
+			wantMessage: "www.a.io",
+			wantStatus:  comment.StatusPending,
+		},
+		{
+			name:        "pre moderation",
+			moderation:  ModerationPre,
+			message:     "fine",
+			wantMessage: "fine",
+			wantStatus:  comment.StatusPending,
+		},
+		{
+			name:    "rejected",
+			filters: []Filter{&MaxLength{Action: ActionReject, Max: 2}},
+			message: "long",
+			want:    ErrRejected,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotMessage, gotStatus string
+			edited := false
+			s := New(&StorageMock{
+				editF: func(id int64, message, status string) error {
+					edited = true
+					gotMessage, gotStatus = message, status
+					return nil
+				},
+			}, WithFilters(tt.filters...), WithModeration(tt.moderation))
+
+			gotErr := s.EditComment(moderator, 1, tt.message)
+			if !errors.Is(gotErr, tt.want) {
+				t.Fatalf("Edit() want = %v, get %v", tt.want, gotErr)
+			}
+			if tt.want != nil {
+				if edited {
+					t.Errorf("Edit() stored rejected message")
+				}
+				return
+			}
+			if gotMessage != tt.wantMessage || gotStatus != tt.wantStatus {
+				t.Errorf(
+					"Edit() stored = %q %q, want %q %q",
+					gotMessage, gotStatus, tt.wantMessage, tt.wantStatus,
+				)
+			}
+		})
+	}
+}
+
+func TestNewFilters(t *testing.T) {
+	var cfg FiltersConfig
+	cfg.MaxLength = FilterConfig{Action: ActionReject, Max: 10}
+	cfg.Links = FilterConfig{Action: ActionFlag, Max: 1}
+
+	filters, err := NewFilters(cfg)
+	if err != nil {
+		t.Fatalf("NewFilters() err = %v", err)
+	}
+	if len(filters) != 2 {
+		t.Errorf("NewFilters() len = %v, want 2", len(filters))
+	}
+
+	cfg.RepeatedChars = FilterConfig{Action: "delete", Max: 1}
+	if _, err := NewFilters(cfg); err == nil {
+		t.Error("NewFilters() want error on unknown action")
+	}
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"CommentTree/internal/entities/comment"
+	"CommentTree/internal/entities/event"
+	"CommentTree/internal/entities/user"
+	"CommentTree/internal/storage"
+)
+
+// ModerationQueue returns pending comments from all threads, oldest first.
+func (s *Service) ModerationQueue(u user.User, page int) (*comment.CommentView, error) {
+	const op = "internal.service.ModerationQueue"
+
+	if !u.IsModerator() {
+		return nil, fmt.Errorf("%w: %s", ErrForbidden, "only for moderators")
+	}
+	if page < 0 {
+		return nil, fmt.Errorf("%w: %s", ErrWrongData, "wrong page")
+	}
+
+	result, err := s.str.Comments(0, &comment.GetterOpts{
+		SearchGlobal: true,
+		Page:         page,
+		Status:       comment.StatusPending,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: (%w)%w", op, ErrStorageInternal, err)
+	}
+
+	return result, nil
+}
+
+func (s *Service) ApproveComment(u user.User, id int64) error {
+	return s.setStatus(u, id, comment.StatusApproved)
+}
+
+func (s *Service) RejectComment(u user.User, id int64) error {
+	return s.setStatus(u, id, comment.StatusRejected)
+}
+
+func (s *Service) setStatus(u user.User, id int64, status string) error {
+	const op = "internal.service.setStatus"
+
+	if id <= 0 {
+		return fmt.Errorf("%w: %s", ErrWrongData, "wrong id")
+	}
+	if !u.IsModerator() {
+		return fmt.Errorf("%w: %s", ErrForbidden, "only for moderators")
+	}
+
+	created, err := s.str.SetStatus(id, status)
+	if errors.Is(err, storage.ErrNotAffected) {
+		return fmt.Errorf(
+			"%w: %s %s", ErrNotAffected,
+			"not find comment with this id, or it's already", status,
+		)
+	} else if err != nil {
+		return fmt.Errorf("%s: (%w)%w", op, ErrStorageInternal, err)
+	}
+
+	// For readers approved comment is just created, but comment
+	// approved again after edit is already known to them.
+	if status == comment.StatusApproved && s.pub != nil {
+		c, err := s.str.Get(id)
+		if err != nil {
+			return fmt.Errorf("%s: (%w)%w", op, ErrStorageInternal, err)
+		}
+		typ := event.TypeEdited
+		if created {
+			typ = event.TypeCreated
+		}
+		s.publish(typ, *c)
+	}
+
+	return nil
+}
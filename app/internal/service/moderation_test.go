@@ -0,0 +1,281 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"CommentTree/internal/entities/comment"
+	"CommentTree/internal/entities/user"
+	"CommentTree/internal/storage"
+)
+
+func TestService_CreateModerated(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for receiver constructor.
+		mode string
+
+		want string
+	}{
+		{
+			name: "post moderation",
+			mode: ModerationPost,
+			want: comment.StatusApproved,
+		},
+		{
+			name: "pre moderation",
+			mode: ModerationPre,
+			want: comment.StatusPending,
+		},
+		{
+			name: "unknown mode",
+			mode: "random",
+			want: comment.StatusApproved,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			s := New(&StorageMock{
+				createF: func(c comment.Comment) (int64, error) {
+					got = c.Status
+					return 1, nil
+				},
+			}, WithModeration(tt.mode))
+
+			_, err := s.CreateComment(comment.Comment{
+				Message: "hi", ThreadKey: "article-1",
+			})
+			if err != nil {
+				t.Fatalf("Create() err = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Create() status = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestService_ModerationQueue(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for receiver constructor.
+		str str
+		// Named input parameters for target function.
+		u    user.User
+		page int
+
+		want error
+	}{
+		{
+			name: "good",
+			str: &StorageMock{
+				getF: func(parentID int64, opts *comment.GetterOpts) (*comment.CommentView, error) {
+					if opts.Status != comment.StatusPending || !opts.SearchGlobal {
+						return nil, errors.New("wrong opts")
+					}
+					return &comment.CommentView{}, nil
+				},
+			},
+			u:    moderator,
+			page: 1,
+			want: nil,
+		},
+		{
+			name: "not moderator",
+			str: &StorageMock{
+				getF: func(parentID int64, opts *comment.GetterOpts) (*comment.CommentView, error) {
+					return &comment.CommentView{}, nil
+				},
+			},
+			u:    user.User{ID: 2, Role: user.RoleUser},
+			page: 1,
+			want: ErrForbidden,
+		},
+		{
+			name: "unknown error",
+			str: &StorageMock{
+				getF: func(parentID int64, opts *comment.GetterOpts) (*comment.CommentView, error) {
+					return nil, errors.New("test")
+				},
+			},
+			u:    moderator,
+			page: 1,
+			want: ErrStorageInternal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(tt.str)
+			_, gotErr := s.ModerationQueue(tt.u, tt.page)
+			if !errors.Is(gotErr, tt.want) {
+				t.Errorf("ModerationQueue() want = %v, get %v", tt.want, gotErr)
+			}
+		})
+	}
+}
+
+func TestService_Approve(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for receiver constructor.
+		str str
+		// Named input parameters for target function.
+		u  user.User
+		ID int64
+
+		want error
+	}{
+		{
+			name: "good",
+			str: &StorageMock{
+				statusF: func(id int64, status string) (bool, error) {
+					if status != comment.StatusApproved {
+						return false, errors.New("wrong status")
+					}
+					return true, nil
+				},
+			},
+			u:    moderator,
+			ID:   1,
+			want: nil,
+		},
+		{
+			name: "wrong id",
+			str: &StorageMock{
+				statusF: func(id int64, status string) (bool, error) {
+					return true, nil
+				},
+			},
+			u:    moderator,
+			ID:   0,
+			want: ErrWrongData,
+		},
+		{
+			name: "not moderator",
+			str: &StorageMock{
+				statusF: func(id int64, status string) (bool, error) {
+					return true, nil
+				},
+			},
+			u:    user.User{ID: 2, Role: user.RoleUser},
+			ID:   1,
+			want: ErrForbidden,
+		},
+		{
+			name: "not affected",
+			str: &StorageMock{
+				statusF: func(id int64, status string) (bool, error) {
+					return false, storage.ErrNotAffected
+				},
+			},
+			u:    moderator,
+			ID:   1,
+			want: ErrNotAffected,
+		},
+		{
+			name: "unknown error",
+			str: &StorageMock{
+				statusF: func(id int64, status string) (bool, error) {
+					return false, errors.New("test")
+				},
+			},
+			u:    moderator,
+			ID:   1,
+			want: ErrStorageInternal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(tt.str)
+			gotErr := s.ApproveComment(tt.u, tt.ID)
+			if !errors.Is(gotErr, tt.want) {
+				t.Errorf("Approve() want = %v, get %v", tt.want, gotErr)
+			}
+		})
+	}
+}
+
+func TestService_Reject(t *testing.T) {
+	var got string
+	s := New(&StorageMock{
+		statusF: func(id int64, status string) (bool, error) {
+			got = status
+			return true, nil
+		},
+	})
+
+	if err := s.RejectComment(moderator, 1); err != nil {
+		t.Fatalf("Reject() err = %v", err)
+	}
+	if got != comment.StatusRejected {
+		t.Errorf("Reject() status = %v, want %v", got, comment.StatusRejected)
+	}
+}
+
+func TestService_ReadsAnyStatus(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		u    user.User
+		want bool
+	}{
+		{
+			name: "anonymous",
+			u:    user.User{},
+			want: false,
+		},
+		{
+			name: "user",
+			u:    user.User{ID: 2, Role: user.RoleUser},
+			want: false,
+		},
+		{
+			name: "moderator",
+			u:    moderator,
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Every read by id gets the same flag.
+			var got []bool
+			s := New(&StorageMock{
+				oneF: func(id int64, anyStatus bool) (*comment.SingleView, error) {
+					got = append(got, anyStatus)
+					return &comment.SingleView{}, nil
+				},
+				pathF: func(id int64, anyStatus bool) ([]comment.Comment, error) {
+					got = append(got, anyStatus)
+					return nil, nil
+				},
+				revsF: func(id int64, anyStatus bool) ([]comment.Revision, error) {
+					got = append(got, anyStatus)
+					return nil, nil
+				},
+				treeF: func(id int64, opts *comment.TreeOpts) (*comment.TreeNode, error) {
+					got = append(got, opts.AnyStatus)
+					return &comment.TreeNode{}, nil
+				},
+			})
+
+			if _, err := s.Comment(tt.u, 1); err != nil {
+				t.Fatalf("Comment() err = %v", err)
+			}
+			if _, err := s.Path(tt.u, 1); err != nil {
+				t.Fatalf("Path() err = %v", err)
+			}
+			if _, err := s.Revisions(tt.u, 1); err != nil {
+				t.Fatalf("Revisions() err = %v", err)
+			}
+			if _, err := s.Tree(tt.u, 1, nil); err != nil {
+				t.Fatalf("Tree() err = %v", err)
+			}
+
+			for i, anyStatus := range got {
+				if anyStatus != tt.want {
+					t.Errorf("read %d anyStatus = %v, want %v", i, anyStatus, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"CommentTree/internal/entities/comment"
+	"CommentTree/internal/entities/user"
+	"CommentTree/internal/storage"
+)
+
+func (s *Service) AddReaction(u user.User, id int64, kind string) error {
+	const op = "internal.service.AddReaction"
+
+	if id <= 0 {
+		return fmt.Errorf("%w: %s", ErrWrongData, "wrong id")
+	}
+	if !comment.ValidReaction(kind) {
+		return fmt.Errorf("%w: %s", ErrWrongData, "unknown reaction")
+	}
+	if u.ID <= 0 {
+		return ErrUnauthorized
+	}
+
+	err := s.str.AddReaction(id, u.ID, kind)
+	if errors.Is(err, storage.ErrNotFound) {
+		return ErrNotFound
+	} else if err != nil {
+		return fmt.Errorf("%s: (%w)%w", op, ErrStorageInternal, err)
+	}
+
+	return nil
+}
+
+func (s *Service) RemoveReaction(u user.User, id int64, kind string) error {
+	const op = "internal.service.RemoveReaction"
+
+	if id <= 0 {
+		return fmt.Errorf("%w: %s", ErrWrongData, "wrong id")
+	}
+	if !comment.ValidReaction(kind) {
+		return fmt.Errorf("%w: %s", ErrWrongData, "unknown reaction")
+	}
+	if u.ID <= 0 {
+		return ErrUnauthorized
+	}
+
+	err := s.str.RemoveReaction(id, u.ID, kind)
+	if errors.Is(err, storage.ErrNotAffected) {
+		return fmt.Errorf(
+			"%w: %s", ErrNotAffected, "not find reaction on this comment",
+		)
+	} else if err != nil {
+		return fmt.Errorf("%s: (%w)%w", op, ErrStorageInternal, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"CommentTree/internal/entities/comment"
+	"CommentTree/internal/entities/user"
+	"CommentTree/internal/storage"
+)
+
+func TestService_AddReaction(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for receiver constructor.
+		str str
+		// Named input parameters for target function.
+		u    user.User
+		ID   int64
+		kind string
+
+		want error
+	}{
+		{
+			name: "good",
+			str: &StorageMock{
+				reactF: func(commentID, userID int64, kind string) error {
+					return nil
+				},
+			},
+			u:    user.User{ID: 2},
+			ID:   1,
+			kind: comment.ReactionUp,
+			want: nil,
+		},
+		{
+			name: "wrong id",
+			str: &StorageMock{
+				reactF: func(commentID, userID int64, kind string) error {
+					return nil
+				},
+			},
+			u:    user.User{ID: 2},
+			ID:   0,
+			kind: comment.ReactionUp,
+			want: ErrWrongData,
+		},
+		{
+			name: "unknown reaction",
+			str: &StorageMock{
+				reactF: func(commentID, userID int64, kind string) error {
+					return nil
+				},
+			},
+			u:    user.User{ID: 2},
+			ID:   1,
+			kind: "like",
+			want: ErrWrongData,
+		},
+		{
+			name: "anonymous",
+			str: &StorageMock{
+				reactF: func(commentID, userID int64, kind string) error {
+					return nil
+				},
+			},
+			ID:   1,
+			kind: comment.ReactionUp,
+			want: ErrUnauthorized,
+		},
+		{
+			name: "not found",
+			str: &StorageMock{
+				reactF: func(commentID, userID int64, kind string) error {
+					return storage.ErrNotFound
+				},
+			},
+			u:    user.User{ID: 2},
+			ID:   1,
+			kind: comment.Emojis[0],
+			want: ErrNotFound,
+		},
+		{
+			name: "unknown error",
+			str: &StorageMock{
+				reactF: func(commentID, userID int64, kind string) error {
+					return errors.New("test")
+				},
+			},
+			u:    user.User{ID: 2},
+			ID:   1,
+			kind: comment.ReactionDown,
+			want: ErrStorageInternal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(tt.str)
+			gotErr := s.AddReaction(tt.u, tt.ID, tt.kind)
+			if !errors.Is(gotErr, tt.want) {
+				t.Errorf("AddReaction() want = %v, get %v", tt.want, gotErr)
+			}
+		})
+	}
+}
+
+func TestService_RemoveReaction(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for receiver constructor.
+		str str
+		// Named input parameters for target function.
+		u    user.User
+		ID   int64
+		kind string
+
+		want error
+	}{
+		{
+			name: "good",
+			str: &StorageMock{
+				unreact: func(commentID, userID int64, kind string) error {
+					return nil
+				},
+			},
+			u:    user.User{ID: 2},
+			ID:   1,
+			kind: comment.ReactionUp,
+			want: nil,
+		},
+		{
+			name: "anonymous",
+			str: &StorageMock{
+				unreact: func(commentID, userID int64, kind string) error {
+					return nil
+				},
+			},
+			ID:   1,
+			kind: comment.ReactionUp,
+			want: ErrUnauthorized,
+		},
+		{
+			name: "not affected",
+			str: &StorageMock{
+				unreact: func(commentID, userID int64, kind string) error {
+					return storage.ErrNotAffected
+				},
+			},
+			u:    user.User{ID: 2},
+			ID:   1,
+			kind: comment.ReactionUp,
+			want: ErrNotAffected,
+		},
+		{
+			name: "unknown error",
+			str: &StorageMock{
+				unreact: func(commentID, userID int64, kind string) error {
+					return errors.New("test")
+				},
+			},
+			u:    user.User{ID: 2},
+			ID:   1,
+			kind: comment.ReactionUp,
+			want: ErrStorageInternal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(tt.str)
+			gotErr := s.RemoveReaction(tt.u, tt.ID, tt.kind)
+			if !errors.Is(gotErr, tt.want) {
+				t.Errorf("RemoveReaction() want = %v, get %v", tt.want, gotErr)
+			}
+		})
+	}
+}
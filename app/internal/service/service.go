@@ -4,6 +4,16 @@ import (
 	"errors"
 
 	"CommentTree/internal/entities/comment"
+	"CommentTree/internal/entities/event"
+	"CommentTree/internal/entities/user"
+	"CommentTree/internal/entities/webhook"
+)
+
+const (
+	// Moderation modes, with post comments are published at once,
+	// with pre they wait for approve of moderator.
+	ModerationPost = "post"
+	ModerationPre  = "pre"
 )
 
 var (
@@ -11,22 +21,94 @@ var (
 	ErrNotAffected     = errors.New("not affected")
 	ErrNotFound        = errors.New("not found")
 	ErrStorageInternal = errors.New("internal storage error")
+	ErrUnauthorized    = errors.New("unauthorized")
+	ErrForbidden       = errors.New("forbidden")
+	ErrAlreadyExists   = errors.New("already exists")
 )
 
 type str interface {
 	CreateComment(c comment.Comment) (int64, error)
 	Comments(parentID int64, opts *comment.GetterOpts) (*comment.CommentView, error)
-	DeleteComment(id int64) error
+	Get(id int64) (*comment.Comment, error)
+	Comment(id int64, anyStatus bool) (*comment.SingleView, error)
+	Tree(id int64, opts *comment.TreeOpts) (*comment.TreeNode, error)
+	Path(id int64, anyStatus bool) ([]comment.Comment, error)
+	EditComment(id int64, message, status string) error
+	Revisions(id int64, anyStatus bool) ([]comment.Revision, error)
+	DeleteComment(id, userID int64, cascade bool) error
+	RestoreComment(id int64) error
+	AddReaction(commentID, userID int64, kind string) error
+	RemoveReaction(commentID, userID int64, kind string) error
+	SetStatus(id int64, status string) (bool, error)
+	CreateWebhook(w webhook.Webhook) (webhook.Webhook, error)
+	Webhooks() ([]webhook.Webhook, error)
+	DeleteWebhook(id int64) error
+
+	CreateUser(u user.User, tokenHash string) (int64, error)
+	UserByToken(tokenHash string) (user.User, error)
+}
+
+type publisher interface {
+	Publish(e event.Event)
 }
 
 type Service struct {
 	str
+	moderation string
+	filters    []Filter
+	pub        publisher
 }
 
-func New(str str) *Service {
-	return &Service{
-		str: str,
+type Option func(s *Service)
+
+// WithModeration sets moderation mode, unknown mode means ModerationPost.
+func WithModeration(mode string) Option {
+	return func(s *Service) {
+		if mode == ModerationPre {
+			s.moderation = mode
+		}
 	}
 }
 
+// WithFilters sets chain of filters for messages of new and edited comments.
+func WithFilters(filters ...Filter) Option {
+	return func(s *Service) {
+		s.filters = append(s.filters, filters...)
+	}
+}
+
+// WithPublisher sets receiver of events about comments changes.
+func WithPublisher(p publisher) Option {
+	return func(s *Service) {
+		s.pub = p
+	}
+}
+
+func New(str str, opts ...Option) *Service {
+	s := &Service{
+		str:        str,
+		moderation: ModerationPost,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
 func (s *Service) Shutdown() {}
+
+// publish sends event with comment c if there is publisher,
+// events about deletion have no comment and are sent apart.
+func (s *Service) publish(typ string, c comment.Comment) {
+	if s.pub == nil {
+		return
+	}
+
+	s.pub.Publish(event.Event{
+		Type:      typ,
+		CommentID: c.ID,
+		ParentID:  c.ParentID,
+		Comment:   &c,
+	})
+}
@@ -5,13 +5,30 @@ import (
 	"testing"
 
 	"CommentTree/internal/entities/comment"
+	"CommentTree/internal/entities/user"
+	"CommentTree/internal/entities/webhook"
 	"CommentTree/internal/storage"
 )
 
 type StorageMock struct {
 	createF func(c comment.Comment) (int64, error)
 	getF    func(parentID int64, opts *comment.GetterOpts) (*comment.CommentView, error)
-	deleteF func(id int64) error
+	deleteF func(id, userID int64, cascade bool) error
+	restF   func(id int64) error
+	editF   func(id int64, message, status string) error
+	revsF   func(id int64, anyStatus bool) ([]comment.Revision, error)
+	oneF    func(id int64, anyStatus bool) (*comment.SingleView, error)
+	treeF   func(id int64, opts *comment.TreeOpts) (*comment.TreeNode, error)
+	pathF   func(id int64, anyStatus bool) ([]comment.Comment, error)
+	getOneF func(id int64) (*comment.Comment, error)
+	userF   func(u user.User, tokenHash string) (int64, error)
+	tokenF  func(tokenHash string) (user.User, error)
+	reactF  func(commentID, userID int64, kind string) error
+	unreact func(commentID, userID int64, kind string) error
+	statusF func(id int64, status string) (bool, error)
+	hookF   func(w webhook.Webhook) (webhook.Webhook, error)
+	hooksF  func() ([]webhook.Webhook, error)
+	unhookF func(id int64) error
 }
 
 func (sm *StorageMock) CreateComment(c comment.Comment) (int64, error) {
@@ -22,10 +39,73 @@ func (sm *StorageMock) Comments(parentID int64, opts *comment.GetterOpts) (*comm
 	return sm.getF(parentID, opts)
 }
 
-func (sm *StorageMock) DeleteComment(id int64) error {
-	return sm.deleteF(id)
+func (sm *StorageMock) DeleteComment(id, userID int64, cascade bool) error {
+	return sm.deleteF(id, userID, cascade)
 }
 
+func (sm *StorageMock) RestoreComment(id int64) error {
+	return sm.restF(id)
+}
+
+func (sm *StorageMock) EditComment(id int64, message, status string) error {
+	return sm.editF(id, message, status)
+}
+
+func (sm *StorageMock) Revisions(id int64, anyStatus bool) ([]comment.Revision, error) {
+	return sm.revsF(id, anyStatus)
+}
+
+func (sm *StorageMock) Comment(id int64, anyStatus bool) (*comment.SingleView, error) {
+	return sm.oneF(id, anyStatus)
+}
+
+func (sm *StorageMock) Get(id int64) (*comment.Comment, error) {
+	return sm.getOneF(id)
+}
+
+func (sm *StorageMock) CreateUser(u user.User, tokenHash string) (int64, error) {
+	return sm.userF(u, tokenHash)
+}
+
+func (sm *StorageMock) UserByToken(tokenHash string) (user.User, error) {
+	return sm.tokenF(tokenHash)
+}
+
+func (sm *StorageMock) Tree(id int64, opts *comment.TreeOpts) (*comment.TreeNode, error) {
+	return sm.treeF(id, opts)
+}
+
+func (sm *StorageMock) Path(id int64, anyStatus bool) ([]comment.Comment, error) {
+	return sm.pathF(id, anyStatus)
+}
+
+func (sm *StorageMock) AddReaction(commentID, userID int64, kind string) error {
+	return sm.reactF(commentID, userID, kind)
+}
+
+func (sm *StorageMock) RemoveReaction(commentID, userID int64, kind string) error {
+	return sm.unreact(commentID, userID, kind)
+}
+
+func (sm *StorageMock) SetStatus(id int64, status string) (bool, error) {
+	return sm.statusF(id, status)
+}
+
+func (sm *StorageMock) CreateWebhook(w webhook.Webhook) (webhook.Webhook, error) {
+	return sm.hookF(w)
+}
+
+func (sm *StorageMock) Webhooks() ([]webhook.Webhook, error) {
+	return sm.hooksF()
+}
+
+func (sm *StorageMock) DeleteWebhook(id int64) error {
+	return sm.unhookF(id)
+}
+
+// Moderator can change any comment, so tests don't depend on authorship.
+var moderator = user.User{ID: 1, Role: user.RoleModerator}
+
 func TestService_Create(t *testing.T) {
 	tests := []struct {
 		name string // description of this test case
@@ -43,10 +123,23 @@ func TestService_Create(t *testing.T) {
 				},
 			},
 			c: comment.Comment{
-				Message: "hi",
+				Message:   "hi",
+				ThreadKey: "article-1",
 			},
 			want: nil,
 		},
+		{
+			name: "root without thread key",
+			str: &StorageMock{
+				createF: func(c comment.Comment) (int64, error) {
+					return 0, nil
+				},
+			},
+			c: comment.Comment{
+				Message: "hi",
+			},
+			want: ErrWrongData,
+		},
 		{
 			name: "wrong comment to create",
 			str: &StorageMock{
@@ -93,7 +186,8 @@ func TestService_Create(t *testing.T) {
 				},
 			},
 			c: comment.Comment{
-				Message: "asd",
+				Message:   "asd",
+				ThreadKey: "article-1",
 			},
 			want: ErrStorageInternal,
 		},
@@ -177,63 +271,671 @@ func TestService_Comments(t *testing.T) {
 }
 
 func TestService_Delete(t *testing.T) {
+	author := user.User{ID: 2, Role: user.RoleUser}
+
 	tests := []struct {
 		name string // description of this test case
 		// Named input parameters for receiver constructor.
 		str str
 		// Named input parameters for target function.
-		ID int64
+		u       user.User
+		ID      int64
+		cascade bool
 
 		want error
 	}{
 		{
 			name: "good",
 			str: &StorageMock{
-				deleteF: func(id int64) error {
+				deleteF: func(id, userID int64, cascade bool) error {
 					return nil
 				},
 			},
+			u:    moderator,
 			ID:   1,
 			want: nil,
 		},
 		{
 			name: "wrong id",
 			str: &StorageMock{
-				deleteF: func(id int64) error {
+				deleteF: func(id, userID int64, cascade bool) error {
 					return nil
 				},
 			},
+			u:    moderator,
 			ID:   0,
 			want: ErrWrongData,
 		},
 		{
 			name: "not affected",
 			str: &StorageMock{
-				deleteF: func(id int64) error {
+				deleteF: func(id, userID int64, cascade bool) error {
 					return storage.ErrNotAffected
 				},
 			},
+			u:    moderator,
 			ID:   1,
 			want: ErrNotAffected,
 		},
 		{
 			name: "unknown error",
 			str: &StorageMock{
-				deleteF: func(id int64) error {
+				deleteF: func(id, userID int64, cascade bool) error {
 					return errors.New("test")
 				},
 			},
+			u:    moderator,
 			ID:   1,
 			want: ErrStorageInternal,
 		},
+		{
+			name: "cascade by moderator",
+			str: &StorageMock{
+				deleteF: func(id, userID int64, cascade bool) error {
+					return nil
+				},
+			},
+			u:       moderator,
+			ID:      1,
+			cascade: true,
+			want:    nil,
+		},
+		{
+			name: "cascade by author",
+			str: &StorageMock{
+				getOneF: func(id int64) (*comment.Comment, error) {
+					return &comment.Comment{ID: id, AuthorID: 2}, nil
+				},
+				deleteF: func(id, userID int64, cascade bool) error {
+					return errors.New("shouldn't be called")
+				},
+			},
+			u:       author,
+			ID:      1,
+			cascade: true,
+			want:    ErrForbidden,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			s := New(tt.str)
-			gotErr := s.DeleteComment(tt.ID)
+			gotErr := s.DeleteComment(tt.u, tt.ID, tt.cascade)
 			if !errors.Is(gotErr, tt.want) {
 				t.Errorf("Delete() want = %v, get %v", tt.want, gotErr)
 			}
 		})
 	}
 }
+
+func TestService_Comment(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for receiver constructor.
+		str str
+		// Named input parameters for target function.
+		ID int64
+
+		want error
+	}{
+		{
+			name: "good",
+			str: &StorageMock{
+				oneF: func(id int64, anyStatus bool) (*comment.SingleView, error) {
+					return &comment.SingleView{}, nil
+				},
+			},
+			ID:   1,
+			want: nil,
+		},
+		{
+			name: "wrong id",
+			str: &StorageMock{
+				oneF: func(id int64, anyStatus bool) (*comment.SingleView, error) {
+					return nil, nil
+				},
+			},
+			ID:   0,
+			want: ErrWrongData,
+		},
+		{
+			name: "not found",
+			str: &StorageMock{
+				oneF: func(id int64, anyStatus bool) (*comment.SingleView, error) {
+					return nil, storage.ErrNotFound
+				},
+			},
+			ID:   1,
+			want: ErrNotFound,
+		},
+		{
+			name: "unknown error",
+			str: &StorageMock{
+				oneF: func(id int64, anyStatus bool) (*comment.SingleView, error) {
+					return nil, errors.New("test")
+				},
+			},
+			ID:   1,
+			want: ErrStorageInternal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(tt.str)
+			_, gotErr := s.Comment(user.User{}, tt.ID)
+			if !errors.Is(gotErr, tt.want) {
+				t.Errorf("Comment() want = %v, get %v", tt.want, gotErr)
+			}
+		})
+	}
+}
+
+func TestService_Tree(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for receiver constructor.
+		str str
+		// Named input parameters for target function.
+		ID   int64
+		opts *comment.TreeOpts
+
+		want error
+	}{
+		{
+			name: "good",
+			str: &StorageMock{
+				treeF: func(id int64, opts *comment.TreeOpts) (*comment.TreeNode, error) {
+					if opts.MaxDepth != comment.DefaultTreeDepth ||
+						opts.Limit != comment.PageElements {
+						return nil, errors.New("defaults not set")
+					}
+					return &comment.TreeNode{}, nil
+				},
+			},
+			ID:   1,
+			opts: nil,
+			want: nil,
+		},
+		{
+			name: "wrong id",
+			str: &StorageMock{
+				treeF: func(id int64, opts *comment.TreeOpts) (*comment.TreeNode, error) {
+					return nil, nil
+				},
+			},
+			ID:   0,
+			want: ErrWrongData,
+		},
+		{
+			name: "too deep",
+			str: &StorageMock{
+				treeF: func(id int64, opts *comment.TreeOpts) (*comment.TreeNode, error) {
+					return nil, nil
+				},
+			},
+			ID:   1,
+			opts: &comment.TreeOpts{MaxDepth: comment.MaxTreeDepth + 1},
+			want: ErrWrongData,
+		},
+		{
+			name: "not found",
+			str: &StorageMock{
+				treeF: func(id int64, opts *comment.TreeOpts) (*comment.TreeNode, error) {
+					return nil, storage.ErrNotFound
+				},
+			},
+			ID:   1,
+			want: ErrNotFound,
+		},
+		{
+			name: "unknown error",
+			str: &StorageMock{
+				treeF: func(id int64, opts *comment.TreeOpts) (*comment.TreeNode, error) {
+					return nil, errors.New("test")
+				},
+			},
+			ID:   1,
+			want: ErrStorageInternal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(tt.str)
+			_, gotErr := s.Tree(user.User{}, tt.ID, tt.opts)
+			if !errors.Is(gotErr, tt.want) {
+				t.Errorf("Tree() want = %v, get %v", tt.want, gotErr)
+			}
+		})
+	}
+}
+
+func TestService_Path(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for receiver constructor.
+		str str
+		// Named input parameters for target function.
+		ID int64
+
+		want error
+	}{
+		{
+			name: "good",
+			str: &StorageMock{
+				pathF: func(id int64, anyStatus bool) ([]comment.Comment, error) {
+					return []comment.Comment{{ID: id}}, nil
+				},
+			},
+			ID:   1,
+			want: nil,
+		},
+		{
+			name: "wrong id",
+			str: &StorageMock{
+				pathF: func(id int64, anyStatus bool) ([]comment.Comment, error) {
+					return nil, nil
+				},
+			},
+			ID:   -1,
+			want: ErrWrongData,
+		},
+		{
+			name: "not found",
+			str: &StorageMock{
+				pathF: func(id int64, anyStatus bool) ([]comment.Comment, error) {
+					return nil, storage.ErrNotFound
+				},
+			},
+			ID:   1,
+			want: ErrNotFound,
+		},
+		{
+			name: "unknown error",
+			str: &StorageMock{
+				pathF: func(id int64, anyStatus bool) ([]comment.Comment, error) {
+					return nil, errors.New("test")
+				},
+			},
+			ID:   1,
+			want: ErrStorageInternal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(tt.str)
+			_, gotErr := s.Path(user.User{}, tt.ID)
+			if !errors.Is(gotErr, tt.want) {
+				t.Errorf("Path() want = %v, get %v", tt.want, gotErr)
+			}
+		})
+	}
+}
+
+func TestService_Restore(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for receiver constructor.
+		str str
+		// Named input parameters for target function.
+		ID int64
+
+		want error
+	}{
+		{
+			name: "good",
+			str: &StorageMock{
+				restF: func(id int64) error {
+					return nil
+				},
+			},
+			ID:   1,
+			want: nil,
+		},
+		{
+			name: "wrong id",
+			str: &StorageMock{
+				restF: func(id int64) error {
+					return nil
+				},
+			},
+			ID:   0,
+			want: ErrWrongData,
+		},
+		{
+			name: "not affected",
+			str: &StorageMock{
+				restF: func(id int64) error {
+					return storage.ErrNotAffected
+				},
+			},
+			ID:   1,
+			want: ErrNotAffected,
+		},
+		{
+			name: "unknown error",
+			str: &StorageMock{
+				restF: func(id int64) error {
+					return errors.New("test")
+				},
+			},
+			ID:   1,
+			want: ErrStorageInternal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(tt.str)
+			gotErr := s.RestoreComment(moderator, tt.ID)
+			if !errors.Is(gotErr, tt.want) {
+				t.Errorf("Restore() want = %v, get %v", tt.want, gotErr)
+			}
+		})
+	}
+}
+
+func TestService_Edit(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for receiver constructor.
+		str str
+		// Named input parameters for target function.
+		ID      int64
+		message string
+
+		want error
+	}{
+		{
+			name: "good",
+			str: &StorageMock{
+				editF: func(id int64, message, status string) error {
+					return nil
+				},
+			},
+			ID:      1,
+			message: "new",
+			want:    nil,
+		},
+		{
+			name: "wrong id",
+			str: &StorageMock{
+				editF: func(id int64, message, status string) error {
+					return nil
+				},
+			},
+			ID:      0,
+			message: "new",
+			want:    ErrWrongData,
+		},
+		{
+			name: "empty message",
+			str: &StorageMock{
+				editF: func(id int64, message, status string) error {
+					return nil
+				},
+			},
+			ID:   1,
+			want: ErrWrongData,
+		},
+		{
+			name: "not affected",
+			str: &StorageMock{
+				editF: func(id int64, message, status string) error {
+					return storage.ErrNotAffected
+				},
+			},
+			ID:      1,
+			message: "new",
+			want:    ErrNotAffected,
+		},
+		{
+			name: "unknown error",
+			str: &StorageMock{
+				editF: func(id int64, message, status string) error {
+					return errors.New("test")
+				},
+			},
+			ID:      1,
+			message: "new",
+			want:    ErrStorageInternal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(tt.str)
+			gotErr := s.EditComment(moderator, tt.ID, tt.message)
+			if !errors.Is(gotErr, tt.want) {
+				t.Errorf("Edit() want = %v, get %v", tt.want, gotErr)
+			}
+		})
+	}
+}
+
+func TestService_Revisions(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for receiver constructor.
+		str str
+		// Named input parameters for target function.
+		ID int64
+
+		want error
+	}{
+		{
+			name: "good",
+			str: &StorageMock{
+				revsF: func(id int64, anyStatus bool) ([]comment.Revision, error) {
+					return []comment.Revision{}, nil
+				},
+			},
+			ID:   1,
+			want: nil,
+		},
+		{
+			name: "wrong id",
+			str: &StorageMock{
+				revsF: func(id int64, anyStatus bool) ([]comment.Revision, error) {
+					return nil, nil
+				},
+			},
+			ID:   0,
+			want: ErrWrongData,
+		},
+		{
+			name: "not found",
+			str: &StorageMock{
+				revsF: func(id int64, anyStatus bool) ([]comment.Revision, error) {
+					return nil, storage.ErrNotFound
+				},
+			},
+			ID:   1,
+			want: ErrNotFound,
+		},
+		{
+			name: "unknown error",
+			str: &StorageMock{
+				revsF: func(id int64, anyStatus bool) ([]comment.Revision, error) {
+					return nil, errors.New("test")
+				},
+			},
+			ID:   1,
+			want: ErrStorageInternal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(tt.str)
+			_, gotErr := s.Revisions(user.User{}, tt.ID)
+			if !errors.Is(gotErr, tt.want) {
+				t.Errorf("Revisions() want = %v, get %v", tt.want, gotErr)
+			}
+		})
+	}
+}
+
+func TestService_CanModify(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for receiver constructor.
+		str str
+		// Named input parameters for target function.
+		u  user.User
+		ID int64
+
+		want error
+	}{
+		{
+			name: "author",
+			str: &StorageMock{
+				getOneF: func(id int64) (*comment.Comment, error) {
+					return &comment.Comment{ID: id, AuthorID: 2}, nil
+				},
+			},
+			u:    user.User{ID: 2, Role: user.RoleUser},
+			ID:   1,
+			want: nil,
+		},
+		{
+			name: "moderator",
+			str: &StorageMock{
+				getOneF: func(id int64) (*comment.Comment, error) {
+					return nil, errors.New("shouldn't be called")
+				},
+			},
+			u:    moderator,
+			ID:   1,
+			want: nil,
+		},
+		{
+			name: "not author",
+			str: &StorageMock{
+				getOneF: func(id int64) (*comment.Comment, error) {
+					return &comment.Comment{ID: id, AuthorID: 3}, nil
+				},
+			},
+			u:    user.User{ID: 2, Role: user.RoleUser},
+			ID:   1,
+			want: ErrForbidden,
+		},
+		{
+			name: "anonymous comment",
+			str: &StorageMock{
+				getOneF: func(id int64) (*comment.Comment, error) {
+					return &comment.Comment{ID: id}, nil
+				},
+			},
+			u:    user.User{ID: 2, Role: user.RoleUser},
+			ID:   1,
+			want: ErrForbidden,
+		},
+		{
+			name: "not found",
+			str: &StorageMock{
+				getOneF: func(id int64) (*comment.Comment, error) {
+					return nil, storage.ErrNotFound
+				},
+			},
+			u:    user.User{ID: 2, Role: user.RoleUser},
+			ID:   1,
+			want: ErrNotAffected,
+		},
+		{
+			name: "unknown error",
+			str: &StorageMock{
+				getOneF: func(id int64) (*comment.Comment, error) {
+					return nil, errors.New("test")
+				},
+			},
+			u:    user.User{ID: 2, Role: user.RoleUser},
+			ID:   1,
+			want: ErrStorageInternal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(tt.str)
+			gotErr := s.canModify(tt.u, tt.ID)
+			if !errors.Is(gotErr, tt.want) {
+				t.Errorf("canModify() want = %v, get %v", tt.want, gotErr)
+			}
+		})
+	}
+}
+
+func TestService_CanRestore(t *testing.T) {
+	author := user.User{ID: 2, Role: user.RoleUser}
+
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for receiver constructor.
+		str str
+		// Named input parameters for target function.
+		u  user.User
+		ID int64
+
+		want error
+	}{
+		{
+			name: "deleted by author",
+			str: &StorageMock{
+				getOneF: func(id int64) (*comment.Comment, error) {
+					return &comment.Comment{ID: id, AuthorID: 2, Deleted: true, DeletedBy: 2}, nil
+				},
+			},
+			u:    author,
+			ID:   1,
+			want: nil,
+		},
+		{
+			name: "deleted by moderator",
+			str: &StorageMock{
+				getOneF: func(id int64) (*comment.Comment, error) {
+					return &comment.Comment{ID: id, AuthorID: 2, Deleted: true, DeletedBy: 1}, nil
+				},
+			},
+			u:    author,
+			ID:   1,
+			want: ErrForbidden,
+		},
+		{
+			name: "moderator restores deletion of author",
+			str: &StorageMock{
+				getOneF: func(id int64) (*comment.Comment, error) {
+					return nil, errors.New("shouldn't be called")
+				},
+			},
+			u:    moderator,
+			ID:   1,
+			want: nil,
+		},
+		{
+			name: "not author",
+			str: &StorageMock{
+				getOneF: func(id int64) (*comment.Comment, error) {
+					return &comment.Comment{ID: id, AuthorID: 3, Deleted: true, DeletedBy: 3}, nil
+				},
+			},
+			u:    author,
+			ID:   1,
+			want: ErrForbidden,
+		},
+		{
+			name: "not found",
+			str: &StorageMock{
+				getOneF: func(id int64) (*comment.Comment, error) {
+					return nil, storage.ErrNotFound
+				},
+			},
+			u:    author,
+			ID:   1,
+			want: ErrNotAffected,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(tt.str)
+			gotErr := s.canRestore(tt.u, tt.ID)
+			if !errors.Is(gotErr, tt.want) {
+				t.Errorf("canRestore() want = %v, get %v", tt.want, gotErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"CommentTree/internal/entities/user"
+	"CommentTree/internal/storage"
+)
+
+const (
+	// Random bytes in access token.
+	TokenBytes = 32
+)
+
+// hashToken returns value which is stored instead of raw token.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// randomToken returns hex of TokenBytes random bytes.
+func randomToken() (string, error) {
+	raw := make([]byte, TokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(raw), nil
+}
+
+// Register creates user and returns it with access token,
+// token can't be restored later, only hash of it stored.
+func (s *Service) Register(name string) (user.User, string, error) {
+	const op = "internal.service.Register"
+
+	if name == "" {
+		return user.User{}, "", fmt.Errorf("%w: %s", ErrWrongData, "empty name")
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return user.User{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	u := user.User{
+		Name: name,
+		Role: user.RoleUser,
+	}
+
+	id, err := s.str.CreateUser(u, hashToken(token))
+	if errors.Is(err, storage.ErrAlreadyExists) {
+		return user.User{}, "", fmt.Errorf("%w: %s", ErrAlreadyExists, "name is taken")
+	} else if err != nil {
+		return user.User{}, "", fmt.Errorf("%s: (%w)%w", op, ErrStorageInternal, err)
+	}
+	u.ID = id
+
+	return u, token, nil
+}
+
+func (s *Service) Authenticate(token string) (user.User, error) {
+	const op = "internal.service.Authenticate"
+
+	if token == "" {
+		return user.User{}, ErrUnauthorized
+	}
+
+	u, err := s.str.UserByToken(hashToken(token))
+	if errors.Is(err, storage.ErrNotFound) {
+		return user.User{}, fmt.Errorf("%w: %s", ErrUnauthorized, "wrong token")
+	} else if err != nil {
+		return user.User{}, fmt.Errorf("%s: (%w)%w", op, ErrStorageInternal, err)
+	}
+
+	return u, nil
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"CommentTree/internal/entities/user"
+	"CommentTree/internal/storage"
+)
+
+func TestService_Register(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for receiver constructor.
+		str str
+		// Named input parameters for target function.
+		userName string
+
+		want error
+	}{
+		{
+			name: "good",
+			str: &StorageMock{
+				userF: func(u user.User, tokenHash string) (int64, error) {
+					if u.Role != user.RoleUser || tokenHash == "" {
+						return 0, errors.New("wrong user")
+					}
+					return 1, nil
+				},
+			},
+			userName: "bob",
+			want:     nil,
+		},
+		{
+			name: "empty name",
+			str: &StorageMock{
+				userF: func(u user.User, tokenHash string) (int64, error) {
+					return 1, nil
+				},
+			},
+			want: ErrWrongData,
+		},
+		{
+			name: "name is taken",
+			str: &StorageMock{
+				userF: func(u user.User, tokenHash string) (int64, error) {
+					return 0, storage.ErrAlreadyExists
+				},
+			},
+			userName: "bob",
+			want:     ErrAlreadyExists,
+		},
+		{
+			name: "unknown error",
+			str: &StorageMock{
+				userF: func(u user.User, tokenHash string) (int64, error) {
+					return 0, errors.New("test")
+				},
+			},
+			userName: "bob",
+			want:     ErrStorageInternal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(tt.str)
+			_, token, gotErr := s.Register(tt.userName)
+			if !errors.Is(gotErr, tt.want) {
+				t.Errorf("Register() want = %v, get %v", tt.want, gotErr)
+			}
+			if gotErr == nil && token == "" {
+				t.Error("Register() returned empty token")
+			}
+		})
+	}
+}
+
+func TestService_Authenticate(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for receiver constructor.
+		str str
+		// Named input parameters for target function.
+		token string
+
+		want error
+	}{
+		{
+			name: "good",
+			str: &StorageMock{
+				tokenF: func(tokenHash string) (user.User, error) {
+					if tokenHash != hashToken("token") {
+						return user.User{}, storage.ErrNotFound
+					}
+					return user.User{ID: 1}, nil
+				},
+			},
+			token: "token",
+			want:  nil,
+		},
+		{
+			name: "empty token",
+			str: &StorageMock{
+				tokenF: func(tokenHash string) (user.User, error) {
+					return user.User{ID: 1}, nil
+				},
+			},
+			want: ErrUnauthorized,
+		},
+		{
+			name: "wrong token",
+			str: &StorageMock{
+				tokenF: func(tokenHash string) (user.User, error) {
+					return user.User{}, storage.ErrNotFound
+				},
+			},
+			token: "token",
+			want:  ErrUnauthorized,
+		},
+		{
+			name: "unknown error",
+			str: &StorageMock{
+				tokenF: func(tokenHash string) (user.User, error) {
+					return user.User{}, errors.New("test")
+				},
+			},
+			token: "token",
+			want:  ErrStorageInternal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(tt.str)
+			_, gotErr := s.Authenticate(tt.token)
+			if !errors.Is(gotErr, tt.want) {
+				t.Errorf("Authenticate() want = %v, get %v", tt.want, gotErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"CommentTree/internal/entities/user"
+	"CommentTree/internal/entities/webhook"
+	"CommentTree/internal/storage"
+)
+
+// CreateWebhook registers w, without secret random one is generated.
+// Secret is returned only here.
+func (s *Service) CreateWebhook(u user.User, w webhook.Webhook) (webhook.Webhook, error) {
+	const op = "internal.service.CreateWebhook"
+
+	if !u.IsModerator() {
+		return webhook.Webhook{}, fmt.Errorf("%w: %s", ErrForbidden, "only for moderators")
+	}
+	if w.URL == "" {
+		return webhook.Webhook{}, fmt.Errorf("%w: %s", ErrWrongData, "empty url")
+	}
+	if len(w.Events) == 0 {
+		return webhook.Webhook{}, fmt.Errorf("%w: %s", ErrWrongData, "empty events")
+	}
+	for _, e := range w.Events {
+		if !webhook.ValidEvent(e) {
+			return webhook.Webhook{}, fmt.Errorf("%w: %s %q", ErrWrongData, "wrong event", e)
+		}
+	}
+
+	if w.Secret == "" {
+		secret, err := randomToken()
+		if err != nil {
+			return webhook.Webhook{}, fmt.Errorf("%s: %w", op, err)
+		}
+		w.Secret = secret
+	}
+
+	w, err := s.str.CreateWebhook(w)
+	if err != nil {
+		return webhook.Webhook{}, fmt.Errorf("%s: (%w)%w", op, ErrStorageInternal, err)
+	}
+
+	return w, nil
+}
+
+func (s *Service) Webhooks(u user.User) ([]webhook.Webhook, error) {
+	const op = "internal.service.Webhooks"
+
+	if !u.IsModerator() {
+		return nil, fmt.Errorf("%w: %s", ErrForbidden, "only for moderators")
+	}
+
+	hooks, err := s.str.Webhooks()
+	if err != nil {
+		return nil, fmt.Errorf("%s: (%w)%w", op, ErrStorageInternal, err)
+	}
+
+	return hooks, nil
+}
+
+func (s *Service) DeleteWebhook(u user.User, id int64) error {
+	const op = "internal.service.DeleteWebhook"
+
+	if id <= 0 {
+		return fmt.Errorf("%w: %s", ErrWrongData, "wrong id")
+	}
+	if !u.IsModerator() {
+		return fmt.Errorf("%w: %s", ErrForbidden, "only for moderators")
+	}
+
+	err := s.str.DeleteWebhook(id)
+	if errors.Is(err, storage.ErrNotAffected) {
+		return fmt.Errorf(
+			"%w: %s", ErrNotAffected, "not find webhook with this id",
+		)
+	} else if err != nil {
+		return fmt.Errorf("%s: (%w)%w", op, ErrStorageInternal, err)
+	}
+
+	return nil
+}
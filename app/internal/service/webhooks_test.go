@@ -0,0 +1,147 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"CommentTree/internal/entities/event"
+	"CommentTree/internal/entities/user"
+	"CommentTree/internal/entities/webhook"
+	"CommentTree/internal/storage"
+)
+
+func TestService_CreateWebhook(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		u user.User
+		w webhook.Webhook
+
+		want error
+	}{
+		{
+			name: "good",
+			u:    moderator,
+			w: webhook.Webhook{
+				URL:    "https://example.com/hook",
+				Events: []string{event.TypeCreated},
+			},
+			want: nil,
+		},
+		{
+			name: "not moderator",
+			u:    user.User{ID: 2, Role: user.RoleUser},
+			w: webhook.Webhook{
+				URL:    "https://example.com/hook",
+				Events: []string{event.TypeCreated},
+			},
+			want: ErrForbidden,
+		},
+		{
+			name: "wrong event",
+			u:    moderator,
+			w: webhook.Webhook{
+				URL:    "https://example.com/hook",
+				Events: []string{"edited"},
+			},
+			want: ErrWrongData,
+		},
+		{
+			name: "empty url",
+			u:    moderator,
+			w: webhook.Webhook{
+				Events: []string{event.TypeDeleted},
+			},
+			want: ErrWrongData,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(&StorageMock{
+				hookF: func(w webhook.Webhook) (webhook.Webhook, error) {
+					w.ID = 1
+					return w, nil
+				},
+			})
+
+			got, err := s.CreateWebhook(tt.u, tt.w)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("CreateWebhook() err = %v, want %v", err, tt.want)
+			}
+			// Secret is generated for signature.
+			if err == nil && got.Secret == "" {
+				t.Errorf("CreateWebhook() secret is empty")
+			}
+		})
+	}
+}
+
+func TestService_DeleteWebhook(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for receiver constructor.
+		str str
+		// Named input parameters for target function.
+		u  user.User
+		id int64
+
+		want error
+	}{
+		{
+			name: "good",
+			str: &StorageMock{
+				unhookF: func(id int64) error {
+					return nil
+				},
+			},
+			u:    moderator,
+			id:   1,
+			want: nil,
+		},
+		{
+			name: "not found",
+			str: &StorageMock{
+				unhookF: func(id int64) error {
+					return storage.ErrNotAffected
+				},
+			},
+			u:    moderator,
+			id:   1,
+			want: ErrNotAffected,
+		},
+		{
+			name: "not moderator",
+			str:  &StorageMock{},
+			u:    user.User{ID: 2, Role: user.RoleUser},
+			id:   1,
+			want: ErrForbidden,
+		},
+		{
+			name: "wrong id",
+			str:  &StorageMock{},
+			u:    moderator,
+			id:   0,
+			want: ErrWrongData,
+		},
+		{
+			name: "storage error",
+			str: &StorageMock{
+				unhookF: func(id int64) error {
+					return errors.New("unknown")
+				},
+			},
+			u:    moderator,
+			id:   1,
+			want: ErrStorageInternal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(tt.str)
+			err := s.DeleteWebhook(tt.u, tt.id)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("DeleteWebhook() err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
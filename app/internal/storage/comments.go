@@ -27,12 +27,15 @@ func (s *Storage) Comments(parentID int64, opts *comment.GetterOpts) (*comment.C
 	var parent comment.Comment
 	var err error
 	if parentID != 0 {
-		parent, err = s.Parent(parentID)
+		// Readers don't see replies of not approved parent
+		// or of it's not approved ancestor.
+		path, err := s.Path(parentID, opts != nil && opts.AnyStatus)
 		if errors.Is(err, errs.ErrDBNotFound) {
 			return nil, ErrNotFound
 		} else if err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
+		parent = path[len(path)-1]
 	}
 
 	childs, err := s.Childs(parentID, opts)
@@ -42,16 +45,118 @@ func (s *Storage) Comments(parentID int64, opts *comment.GetterOpts) (*comment.C
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return &comment.CommentView{
+	view := &comment.CommentView{
 		Parent: parent,
 		Childs: childs,
-	}, nil
+	}
+	// Ranked results have no cursor, they are paged only by page.
+	ranked := opts != nil && comment.RankedSearch(opts.SearchMode)
+	if len(childs) > 0 && childs[len(childs)-1].HaveNext && !ranked {
+		var sort string
+		if opts != nil {
+			sort = opts.Sort
+		}
+		view.NextCursor = comment.NewCursor(childs[len(childs)-1], sort).Encode()
+	}
+
+	return view, nil
+}
+
+func (s *Storage) Get(id int64) (*comment.Comment, error) {
+	const op = "internal.storage.Get"
+
+	c, err := s.Parent(id)
+	if errors.Is(err, errs.ErrDBNotFound) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &c, nil
 }
 
-func (s *Storage) DeleteComment(id int64) error {
+func (s *Storage) Comment(id int64, anyStatus bool) (*comment.SingleView, error) {
+	const op = "internal.storage.Comment"
+
+	c, err := s.Single(id, anyStatus)
+	if errors.Is(err, errs.ErrDBNotFound) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &c, nil
+}
+
+func (s *Storage) Tree(id int64, opts *comment.TreeOpts) (*comment.TreeNode, error) {
+	const op = "internal.storage.Tree"
+
+	tree, err := s.db.Tree(id, opts)
+	if errors.Is(err, errs.ErrDBNotFound) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tree, nil
+}
+
+func (s *Storage) Path(id int64, anyStatus bool) ([]comment.Comment, error) {
+	const op = "internal.storage.Path"
+
+	path, err := s.db.Path(id, anyStatus)
+	if errors.Is(err, errs.ErrDBNotFound) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return path, nil
+}
+
+func (s *Storage) EditComment(id int64, message, status string) error {
+	const op = "internal.storage.Edit"
+
+	err := s.db.EditComment(id, message, status)
+	if errors.Is(err, errs.ErrDBNotAffected) {
+		return ErrNotAffected
+	} else if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) Revisions(id int64, anyStatus bool) ([]comment.Revision, error) {
+	const op = "internal.storage.Revisions"
+
+	revs, err := s.db.Revisions(id, anyStatus)
+	if errors.Is(err, errs.ErrDBNotFound) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return revs, nil
+}
+
+func (s *Storage) DeleteComment(id, userID int64, cascade bool) error {
 	const op = "internal.storage.Delete"
 
-	err := s.db.DeleteComment(id)
+	err := s.db.DeleteComment(id, userID, cascade)
+	if errors.Is(err, errs.ErrDBNotAffected) {
+		return ErrNotAffected
+	} else if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) RestoreComment(id int64) error {
+	const op = "internal.storage.Restore"
+
+	err := s.db.RestoreComment(id)
 	if errors.Is(err, errs.ErrDBNotAffected) {
 		return ErrNotAffected
 	} else if err != nil {
@@ -0,0 +1,72 @@
+package storage_test
+
+import (
+	"errors"
+	"testing"
+
+	"CommentTree/internal/entities/comment"
+	"CommentTree/internal/storage"
+	"CommentTree/internal/storage/memory"
+)
+
+func TestStorage_Comments(t *testing.T) {
+	s := storage.New(memory.New())
+	pending, err := s.CreateComment(comment.Comment{
+		Message: "pending", ThreadKey: "t", Status: comment.StatusPending,
+	})
+	if err != nil {
+		t.Fatalf("CreateComment() err = %v", err)
+	}
+	root, err := s.CreateComment(comment.Comment{Message: "root", ThreadKey: "t"})
+	if err != nil {
+		t.Fatalf("CreateComment() err = %v", err)
+	}
+	reply, err := s.CreateComment(comment.Comment{Message: "reply", ParentID: root})
+	if err != nil {
+		t.Fatalf("CreateComment() err = %v", err)
+	}
+	// Edit sends approved root back to moderation.
+	if _, err := s.SetStatus(root, comment.StatusPending); err != nil {
+		t.Fatalf("SetStatus() err = %v", err)
+	}
+
+	tests := []struct {
+		name     string // description of this test case
+		parentID int64
+		opts     *comment.GetterOpts
+		wantErr  error
+	}{
+		{
+			name:     "reader",
+			parentID: pending,
+			opts:     nil,
+			wantErr:  storage.ErrNotFound,
+		},
+		{
+			name:     "moderator",
+			parentID: pending,
+			opts:     &comment.GetterOpts{AnyStatus: true},
+			wantErr:  nil,
+		},
+		{
+			name:     "reader under hidden grandparent",
+			parentID: reply,
+			opts:     &comment.GetterOpts{},
+			wantErr:  storage.ErrNotFound,
+		},
+		{
+			name:     "moderator under hidden grandparent",
+			parentID: reply,
+			opts:     &comment.GetterOpts{AnyStatus: true},
+			wantErr:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.Comments(tt.parentID, tt.opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Comments() err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
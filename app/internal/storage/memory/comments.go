@@ -0,0 +1,472 @@
+package memory
+
+import (
+	"slices"
+	"strings"
+
+	"CommentTree/internal/entities/comment"
+	"CommentTree/internal/entities/event"
+	"CommentTree/internal/storage/textsearch"
+	"CommentTree/pkg/errs"
+)
+
+// view returns comment with counts, m.mu should be locked.
+func (m *Memory) view(r *record) comment.Comment {
+	c := r.Comment
+	c.ReplyCount = int64(len(m.approvedChildren(c.ID)))
+	c.DescendantCount = m.descendants(c.ID)
+	c.Score, c.Reactions = m.reactionCounts(c.ID)
+
+	// Tombstone keeps place in the tree, but not it's text.
+	if r.deletedAt != nil {
+		c.Deleted = true
+		c.Message = comment.DeletedMessage
+	}
+
+	return c
+}
+
+// approvedChildren returns replies which readers see, m.mu should be locked.
+func (m *Memory) approvedChildren(id int64) []*record {
+	var res []*record
+	for _, childID := range m.children[id] {
+		if r := m.comments[childID]; r.Status == comment.StatusApproved {
+			res = append(res, r)
+		}
+	}
+
+	return res
+}
+
+// descendants counts approved replies on all levels, m.mu should be locked.
+func (m *Memory) descendants(id int64) int64 {
+	var n int64
+	for _, r := range m.approvedChildren(id) {
+		n += 1 + m.descendants(r.ID)
+	}
+
+	return n
+}
+
+func (m *Memory) CreateComment(c comment.Comment) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Can't reply to deleted or not approved comment.
+	if c.ParentID != 0 {
+		parent, ok := m.comments[c.ParentID]
+		if !ok || parent.deletedAt != nil || parent.Status != comment.StatusApproved {
+			return 0, errs.ErrDBViolatesForeignKey
+		}
+		// Reply always belongs to thread of it's parent.
+		c.ThreadKey = parent.ThreadKey
+	}
+	if _, ok := m.users[c.AuthorID]; c.AuthorID > 0 && !ok {
+		return 0, errs.ErrDBViolatesForeignKey
+	}
+
+	if c.Status == "" {
+		c.Status = comment.StatusApproved
+	}
+
+	m.lastID++
+	t := now()
+	r := &record{Comment: comment.Comment{
+		ID:        m.lastID,
+		Message:   c.Message,
+		ParentID:  c.ParentID,
+		AuthorID:  c.AuthorID,
+		ThreadKey: c.ThreadKey,
+		Status:    c.Status,
+		CreatedAt: t,
+		UpdatedAt: t,
+	}, published: c.Status == comment.StatusApproved}
+	m.comments[r.ID] = r
+	m.children[r.ParentID] = append(m.children[r.ParentID], r.ID)
+
+	m.enqueueWebhooks(event.TypeCreated, r)
+
+	return r.ID, nil
+}
+
+func (m *Memory) Parent(id int64) (comment.Comment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	r, ok := m.comments[id]
+	if !ok {
+		return comment.Comment{}, errs.ErrDBNotFound
+	}
+
+	return m.view(r), nil
+}
+
+func (m *Memory) Single(id int64, anyStatus bool) (comment.SingleView, error) {
+	// Path ends with the comment.
+	path, err := m.Path(id, anyStatus)
+	if err != nil {
+		return comment.SingleView{}, err
+	}
+
+	return comment.SingleView{
+		Comment: path[len(path)-1],
+		RootID:  path[0].ID,
+		Depth:   int64(len(path) - 1),
+	}, nil
+}
+
+// Path returns chain of comments from the root, without anyStatus
+// it isn't found if some comment of chain isn't approved.
+func (m *Memory) Path(id int64, anyStatus bool) ([]comment.Comment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	path := []comment.Comment{}
+	for r, ok := m.comments[id]; ok; r, ok = m.comments[r.ParentID] {
+		if !anyStatus && !r.Public() {
+			return nil, errs.ErrDBNotFound
+		}
+		path = append(path, m.view(r))
+	}
+
+	if len(path) == 0 {
+		return nil, errs.ErrDBNotFound
+	}
+	slices.Reverse(path)
+
+	return path, nil
+}
+
+// matches reports that r passes filters of opts,
+// search fields of it's view c are set on the way.
+func matches(r *record, c *comment.Comment, opts *comment.GetterOpts) bool {
+	if !opts.AnyStatus {
+		status := opts.Status
+		if status == "" {
+			status = comment.StatusApproved
+		}
+		if r.Status != status {
+			return false
+		}
+	}
+
+	if opts.ThreadKey != "" && r.ThreadKey != opts.ThreadKey {
+		return false
+	}
+
+	if opts.Substr == "" {
+		return true
+	}
+	// Text of deleted comments is hidden, so we don't search in it.
+	if r.deletedAt != nil {
+		return false
+	}
+
+	switch opts.SearchMode {
+	case comment.SearchFulltext:
+		c.Rank, c.Snippet = textsearch.Fulltext(opts.Substr, r.Message)
+		return c.Rank > 0
+	case comment.SearchFuzzy:
+		c.Similarity = textsearch.WordSimilarity(opts.Substr, r.Message)
+		return c.Similarity >= textsearch.SimilarityThreshold ||
+			strings.Contains(strings.ToLower(r.Message), strings.ToLower(opts.Substr))
+	}
+
+	return strings.Contains(r.Message, opts.Substr)
+}
+
+func (m *Memory) Childs(parentID int64, opts *comment.GetterOpts) ([]comment.Comment, error) {
+	if opts == nil {
+		opts = &comment.GetterOpts{}
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Search in all comments only from the invisible root.
+	var candidates []*record
+	if parentID == 0 && opts.SearchGlobal {
+		for _, r := range m.comments {
+			candidates = append(candidates, r)
+		}
+	} else {
+		for _, id := range m.children[parentID] {
+			candidates = append(candidates, m.comments[id])
+		}
+	}
+
+	coms := []comment.Comment{}
+	for _, r := range candidates {
+		c := m.view(r)
+		if matches(r, &c, opts) {
+			coms = append(coms, c)
+		}
+	}
+
+	ranked := opts.Substr != "" && comment.RankedSearch(opts.SearchMode)
+	less := orderOf(opts, ranked)
+	slices.SortFunc(coms, less)
+
+	if opts.After != nil && !ranked {
+		after := comment.Comment{
+			ID:         opts.After.ID,
+			CreatedAt:  opts.After.CreatedAt,
+			ReplyCount: opts.After.Rank,
+			Score:      opts.After.Rank,
+		}
+		// Position of the first comment after cursor.
+		i, found := slices.BinarySearchFunc(coms, after, less)
+		if found {
+			i++
+		}
+		coms = coms[i:]
+	} else {
+		page := opts.Page
+		if page > 0 {
+			page--
+		}
+		offset := min(comment.PageElements*page, len(coms))
+		coms = coms[offset:]
+	}
+
+	// One more comment shows that we have next page.
+	coms = coms[:min(len(coms), comment.PageElements+1)]
+	for i := range coms {
+		coms[i].HaveNext = true
+	}
+	if len(coms) > comment.PageElements {
+		coms = coms[:comment.PageElements]
+	} else if len(coms) > 0 {
+		coms[len(coms)-1].HaveNext = false
+	}
+
+	return coms, nil
+}
+
+// orderOf returns comparison of comments in order of opts.
+func orderOf(opts *comment.GetterOpts, ranked bool) func(a, b comment.Comment) int {
+	byID := func(a, b comment.Comment) int {
+		return -cmpInt(a.ID, b.ID)
+	}
+
+	switch {
+	case ranked && opts.SearchMode == comment.SearchFulltext:
+		return func(a, b comment.Comment) int {
+			if a.Rank != b.Rank {
+				return -cmpFloat(a.Rank, b.Rank)
+			}
+			return byID(a, b)
+		}
+	case ranked:
+		return func(a, b comment.Comment) int {
+			if a.Similarity != b.Similarity {
+				return -cmpFloat(a.Similarity, b.Similarity)
+			}
+			return byID(a, b)
+		}
+	case opts.Sort == comment.SortNewest:
+		return func(a, b comment.Comment) int {
+			if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+				return -c
+			}
+			return byID(a, b)
+		}
+	case opts.Sort == comment.SortMostReplied:
+		return func(a, b comment.Comment) int {
+			if a.ReplyCount != b.ReplyCount {
+				return -cmpInt(a.ReplyCount, b.ReplyCount)
+			}
+			return byID(a, b)
+		}
+	case opts.Sort == comment.SortTop:
+		return func(a, b comment.Comment) int {
+			if a.Score != b.Score {
+				return -cmpInt(a.Score, b.Score)
+			}
+			return byID(a, b)
+		}
+	}
+
+	return func(a, b comment.Comment) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return cmpInt(a.ID, b.ID)
+	}
+}
+
+func cmpInt(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+
+	return 0
+}
+
+func cmpFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+
+	return 0
+}
+
+func (m *Memory) Tree(id int64, opts *comment.TreeOpts) (*comment.TreeNode, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	r, ok := m.comments[id]
+	if !ok || (!opts.AnyStatus && m.hidden(r)) {
+		return nil, errs.ErrDBNotFound
+	}
+
+	root := &comment.TreeNode{Comment: m.view(r), Replies: []*comment.TreeNode{}}
+	m.grow(root, 0, opts)
+
+	return root, nil
+}
+
+// hidden reports that r or one of it's ancestors isn't approved,
+// readers don't see such comments, m.mu should be locked.
+func (m *Memory) hidden(r *record) bool {
+	for ok := true; ok; r, ok = m.comments[r.ParentID] {
+		if !r.Public() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// grow loads replies of node up to opts.MaxDepth, m.mu should be locked.
+func (m *Memory) grow(node *comment.TreeNode, depth int, opts *comment.TreeOpts) {
+	if depth >= opts.MaxDepth {
+		return
+	}
+
+	for i, r := range m.approvedChildren(node.ID) {
+		// Reply after limit only marks that node have more replies.
+		if i == opts.Limit {
+			if len(node.Replies) > 0 {
+				node.Replies[len(node.Replies)-1].HaveNext = true
+			}
+			return
+		}
+
+		child := &comment.TreeNode{Comment: m.view(r), Replies: []*comment.TreeNode{}}
+		node.Replies = append(node.Replies, child)
+		m.grow(child, depth+1, opts)
+	}
+}
+
+// EditComment saves current message of comment to revisions
+// and replaces it with new one, status isn't changed if it's empty.
+func (m *Memory) EditComment(id int64, message, status string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, ok := m.comments[id]
+	if !ok || r.deletedAt != nil {
+		return errs.ErrDBNotAffected
+	}
+
+	m.lastRevisionID++
+	t := now()
+	m.revisions[id] = append(m.revisions[id], comment.Revision{
+		ID:        m.lastRevisionID,
+		CommentID: id,
+		Message:   r.Message,
+		CreatedAt: t,
+	})
+
+	r.Message = message
+	r.EditedAt = &t
+	r.UpdatedAt = t
+	if status != "" {
+		r.Status = status
+	}
+
+	return nil
+}
+
+func (m *Memory) Revisions(id int64, anyStatus bool) ([]comment.Revision, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	r, ok := m.comments[id]
+	if !ok || (!anyStatus && m.hidden(r)) {
+		return nil, errs.ErrDBNotFound
+	}
+
+	// History of deleted comment is hidden as it's message.
+	revs := []comment.Revision{}
+	if r.deletedAt != nil {
+		return revs, nil
+	}
+
+	return append(revs, m.revisions[id]...), nil
+}
+
+// DeleteComment marks comment as deleted by user and keeps it's replies,
+// with cascade it removes comment with all replies.
+func (m *Memory) DeleteComment(id, userID int64, cascade bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, ok := m.comments[id]
+	if !ok || (!cascade && r.deletedAt != nil) {
+		return errs.ErrDBNotAffected
+	}
+
+	m.enqueueWebhooks(event.TypeDeleted, r)
+
+	if !cascade {
+		t := now()
+		r.deletedAt = &t
+		r.DeletedBy = userID
+		r.UpdatedAt = t
+		return nil
+	}
+
+	siblings := m.children[r.ParentID]
+	m.children[r.ParentID] = slices.DeleteFunc(siblings, func(childID int64) bool {
+		return childID == id
+	})
+	m.remove(id)
+
+	return nil
+}
+
+// remove deletes comment with it's replies, m.mu should be locked.
+func (m *Memory) remove(id int64) {
+	for _, childID := range m.children[id] {
+		m.remove(childID)
+	}
+
+	delete(m.children, id)
+	delete(m.comments, id)
+	delete(m.revisions, id)
+	delete(m.reactions, id)
+}
+
+func (m *Memory) RestoreComment(id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, ok := m.comments[id]
+	if !ok || r.deletedAt == nil {
+		return errs.ErrDBNotAffected
+	}
+
+	r.deletedAt = nil
+	r.DeletedBy = 0
+	r.UpdatedAt = now()
+
+	return nil
+}
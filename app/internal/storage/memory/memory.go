@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"CommentTree/internal/entities/comment"
+	"CommentTree/internal/entities/user"
+	"CommentTree/internal/entities/webhook"
+)
+
+type record struct {
+	// Stored fields, counts are computed on read.
+	comment.Comment
+	deletedAt *time.Time
+	// Comment was approved once, so readers already got it.
+	published bool
+}
+
+type outboxRecord struct {
+	webhook.Delivery
+	nextAttemptAt time.Time
+	lastError     string
+}
+
+// Memory keeps everything in maps, it has the same behavior
+// as postgres storage, but data is lost on restart.
+type Memory struct {
+	mu sync.RWMutex
+
+	lastID   int64
+	comments map[int64]*record
+	// Replies in order of creation, roots are under 0.
+	children map[int64][]int64
+
+	lastRevisionID int64
+	revisions      map[int64][]comment.Revision
+
+	// Kinds of reactions of users on comment.
+	reactions map[int64]map[int64]map[string]struct{}
+
+	lastUserID int64
+	users      map[int64]user.User
+	// User id by hash of token.
+	tokens map[string]int64
+
+	lastWebhookID  int64
+	webhooks       map[int64]webhook.Webhook
+	lastDeliveryID int64
+	// Not delivered events in order of creation.
+	outbox []*outboxRecord
+}
+
+func New() *Memory {
+	return &Memory{
+		comments:  make(map[int64]*record),
+		children:  make(map[int64][]int64),
+		revisions: make(map[int64][]comment.Revision),
+		reactions: make(map[int64]map[int64]map[string]struct{}),
+		users:     make(map[int64]user.User),
+		tokens:    make(map[string]int64),
+		webhooks:  make(map[int64]webhook.Webhook),
+	}
+}
+
+func (m *Memory) Shutdown() {}
+
+// now returns time with precision of postgres timestamps,
+// so it's the same after encoding in cursor.
+func now() time.Time {
+	return time.Now().Truncate(time.Microsecond)
+}
@@ -0,0 +1,271 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"CommentTree/internal/entities/comment"
+	"CommentTree/internal/entities/user"
+	"CommentTree/internal/storage/storagetest"
+	"CommentTree/pkg/errs"
+)
+
+// fill creates n root comments of thread "t" with messages "comment i".
+func fill(t *testing.T, m *Memory, n int) []int64 {
+	t.Helper()
+
+	ids := make([]int64, 0, n)
+	for i := range n {
+		id, err := m.CreateComment(comment.Comment{
+			Message:   fmt.Sprintf("comment %d", i),
+			ThreadKey: "t",
+		})
+		if err != nil {
+			t.Fatalf("CreateComment() err = %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+func TestMemory_Childs(t *testing.T) {
+	m := New()
+	ids := fill(t, m, comment.PageElements+2)
+
+	tests := []struct {
+		name      string // description of this test case
+		opts      *comment.GetterOpts
+		wantLen   int
+		wantFirst int64
+		wantNext  bool
+	}{
+		{
+			name:      "first page",
+			opts:      &comment.GetterOpts{},
+			wantLen:   comment.PageElements,
+			wantFirst: ids[0],
+			wantNext:  true,
+		},
+		{
+			name:      "last page",
+			opts:      &comment.GetterOpts{Page: 2},
+			wantLen:   2,
+			wantFirst: ids[comment.PageElements],
+			wantNext:  false,
+		},
+		{
+			name:      "newest",
+			opts:      &comment.GetterOpts{Sort: comment.SortNewest},
+			wantLen:   comment.PageElements,
+			wantFirst: ids[len(ids)-1],
+			wantNext:  true,
+		},
+		{
+			name:      "substr",
+			opts:      &comment.GetterOpts{Substr: "comment 11"},
+			wantLen:   1,
+			wantFirst: ids[11],
+			wantNext:  false,
+		},
+		{
+			name: "other thread",
+			opts: &comment.GetterOpts{ThreadKey: "other"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := m.Childs(0, tt.opts)
+			if err != nil {
+				t.Fatalf("Childs() err = %v", err)
+			}
+			if len(got) != tt.wantLen {
+				t.Fatalf("Childs() len = %v, want %v", len(got), tt.wantLen)
+			}
+			if len(got) == 0 {
+				return
+			}
+			if got[0].ID != tt.wantFirst {
+				t.Errorf("Childs() first = %v, want %v", got[0].ID, tt.wantFirst)
+			}
+			if last := got[len(got)-1]; last.HaveNext != tt.wantNext {
+				t.Errorf("Childs() HaveNext = %v, want %v", last.HaveNext, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestMemory_Childs_Cursor(t *testing.T) {
+	m := New()
+	ids := fill(t, m, comment.PageElements+2)
+
+	first, err := m.Childs(0, &comment.GetterOpts{})
+	if err != nil {
+		t.Fatalf("Childs() err = %v", err)
+	}
+
+	cursor := comment.NewCursor(first[len(first)-1], comment.SortOldest)
+	got, err := m.Childs(0, &comment.GetterOpts{After: &cursor})
+	if err != nil {
+		t.Fatalf("Childs() err = %v", err)
+	}
+	if len(got) != 2 || got[0].ID != ids[comment.PageElements] {
+		t.Errorf("Childs() = %v, want comments after %v", got, cursor.ID)
+	}
+}
+
+func TestMemory_Childs_Search(t *testing.T) {
+	m := New()
+	for _, msg := range []string{
+		"The cats are sleeping",
+		"Dogs are barking",
+		"Hello world",
+	} {
+		if _, err := m.CreateComment(comment.Comment{Message: msg, ThreadKey: "t"}); err != nil {
+			t.Fatalf("CreateComment() err = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name string // description of this test case
+		mode string
+		text string
+		want string
+	}{
+		{
+			name: "fulltext with stemming",
+			mode: comment.SearchFulltext,
+			text: "cat",
+			want: "The cats are sleeping",
+		},
+		{
+			name: "fulltext with negation",
+			mode: comment.SearchFulltext,
+			text: "are -cats -hello",
+			want: "Dogs are barking",
+		},
+		{
+			name: "fuzzy with typo",
+			mode: comment.SearchFuzzy,
+			text: "helo",
+			want: "Hello world",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := m.Childs(0, &comment.GetterOpts{
+				SearchGlobal: true,
+				Substr:       tt.text,
+				SearchMode:   tt.mode,
+			})
+			if err != nil {
+				t.Fatalf("Childs() err = %v", err)
+			}
+			if len(got) != 1 || got[0].Message != tt.want {
+				t.Errorf("Childs() = %v, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemory_DeleteComment(t *testing.T) {
+	tests := []struct {
+		name    string // description of this test case
+		cascade bool
+		wantErr error
+	}{
+		{
+			name:    "soft",
+			cascade: false,
+		},
+		{
+			name:    "cascade",
+			cascade: true,
+			wantErr: errs.ErrDBNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New()
+			root := fill(t, m, 1)[0]
+			reply, err := m.CreateComment(comment.Comment{Message: "reply", ParentID: root})
+			if err != nil {
+				t.Fatalf("CreateComment() err = %v", err)
+			}
+
+			if err := m.DeleteComment(root, 0, tt.cascade); err != nil {
+				t.Fatalf("DeleteComment() err = %v", err)
+			}
+
+			_, err = m.Parent(reply)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Parent() err = %v, want %v", err, tt.wantErr)
+			}
+
+			got, err := m.Parent(root)
+			if err == nil && (!got.Deleted || got.Message != comment.DeletedMessage) {
+				t.Errorf("Parent() = %v, want tombstone", got)
+			}
+		})
+	}
+}
+
+func TestMemory_Tree(t *testing.T) {
+	m := New()
+	root := fill(t, m, 1)[0]
+	parent := root
+	for range 3 {
+		id, err := m.CreateComment(comment.Comment{Message: "reply", ParentID: parent})
+		if err != nil {
+			t.Fatalf("CreateComment() err = %v", err)
+		}
+		parent = id
+	}
+
+	got, err := m.Tree(root, &comment.TreeOpts{MaxDepth: 2, Limit: 10})
+	if err != nil {
+		t.Fatalf("Tree() err = %v", err)
+	}
+
+	depth := 0
+	for node := got; len(node.Replies) > 0; node = node.Replies[0] {
+		depth++
+	}
+	if depth != 2 || got.DescendantCount != 3 {
+		t.Errorf("Tree() depth = %v, descendants = %v, want 2 and 3", depth, got.DescendantCount)
+	}
+}
+
+func TestMemory_AddReaction(t *testing.T) {
+	m := New()
+	id := fill(t, m, 1)[0]
+	userID, err := m.CreateUser(user.User{Name: "bob"}, "hash")
+	if err != nil {
+		t.Fatalf("CreateUser() err = %v", err)
+	}
+
+	for _, kind := range []string{comment.ReactionUp, comment.ReactionDown, comment.ReactionDown} {
+		if err := m.AddReaction(id, userID, kind); err != nil {
+			t.Fatalf("AddReaction() err = %v", err)
+		}
+	}
+
+	got, err := m.Parent(id)
+	if err != nil {
+		t.Fatalf("Parent() err = %v", err)
+	}
+	if got.Score != -1 || got.Reactions[comment.ReactionUp] != 0 {
+		t.Errorf("Parent() score = %v, reactions = %v, want only down vote", got.Score, got.Reactions)
+	}
+
+	if err := m.AddReaction(id, userID+1, comment.ReactionUp); !errors.Is(err, errs.ErrDBViolatesForeignKey) {
+		t.Errorf("AddReaction() err = %v, want %v", err, errs.ErrDBViolatesForeignKey)
+	}
+}
+
+func TestMemory_Conformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storagetest.DB {
+		return New()
+	})
+}
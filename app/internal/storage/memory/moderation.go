@@ -0,0 +1,33 @@
+package memory
+
+import (
+	"CommentTree/internal/entities/comment"
+	"CommentTree/internal/entities/event"
+	"CommentTree/pkg/errs"
+)
+
+// SetStatus changes moderation status of comment, comment
+// which already has this status isn't affected. It reports that comment
+// is approved for the first time, so it's created for readers.
+func (m *Memory) SetStatus(id int64, status string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, ok := m.comments[id]
+	if !ok || r.Status == status {
+		return false, errs.ErrDBNotAffected
+	}
+
+	r.Status = status
+	r.UpdatedAt = now()
+
+	// For webhooks approved comment is just created, edits
+	// aren't sent to them.
+	created := status == comment.StatusApproved && !r.published
+	if created {
+		r.published = true
+		m.enqueueWebhooks(event.TypeCreated, r)
+	}
+
+	return created, nil
+}
@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"CommentTree/internal/entities/comment"
+	"CommentTree/pkg/errs"
+)
+
+// AddReaction sets reaction of user on comment, new vote replaces
+// opposite one. Setting existing reaction again isn't error.
+func (m *Memory) AddReaction(commentID, userID int64, kind string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if r, ok := m.comments[commentID]; !ok || r.deletedAt != nil || !r.Public() {
+		return errs.ErrDBNotAffected
+	}
+	if _, ok := m.users[userID]; !ok {
+		return errs.ErrDBViolatesForeignKey
+	}
+
+	byUser, ok := m.reactions[commentID]
+	if !ok {
+		byUser = make(map[int64]map[string]struct{})
+		m.reactions[commentID] = byUser
+	}
+	kinds, ok := byUser[userID]
+	if !ok {
+		kinds = make(map[string]struct{})
+		byUser[userID] = kinds
+	}
+
+	delete(kinds, comment.OppositeVote(kind))
+	kinds[kind] = struct{}{}
+
+	return nil
+}
+
+func (m *Memory) RemoveReaction(commentID, userID int64, kind string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	kinds := m.reactions[commentID][userID]
+	if _, ok := kinds[kind]; !ok {
+		return errs.ErrDBNotAffected
+	}
+	delete(kinds, kind)
+
+	return nil
+}
+
+// reactionCounts returns score and counts of reactions
+// of comment id, m.mu should be locked.
+func (m *Memory) reactionCounts(id int64) (int64, map[string]int64) {
+	var score int64
+	var counts map[string]int64
+	for _, kinds := range m.reactions[id] {
+		for kind := range kinds {
+			if counts == nil {
+				counts = make(map[string]int64)
+			}
+			counts[kind]++
+
+			switch kind {
+			case comment.ReactionUp:
+				score++
+			case comment.ReactionDown:
+				score--
+			}
+		}
+	}
+
+	return score, counts
+}
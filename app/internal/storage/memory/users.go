@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"CommentTree/internal/entities/user"
+	"CommentTree/pkg/errs"
+)
+
+func (m *Memory) CreateUser(u user.User, tokenHash string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.tokens[tokenHash]; ok {
+		return 0, errs.ErrDBAlreadyExists
+	}
+	for _, other := range m.users {
+		if other.Name == u.Name {
+			return 0, errs.ErrDBAlreadyExists
+		}
+	}
+
+	if u.Role == "" {
+		u.Role = user.RoleUser
+	}
+
+	m.lastUserID++
+	u.ID = m.lastUserID
+	m.users[u.ID] = u
+	m.tokens[tokenHash] = u.ID
+
+	return u.ID, nil
+}
+
+func (m *Memory) UserByToken(tokenHash string) (user.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	id, ok := m.tokens[tokenHash]
+	if !ok {
+		return user.User{}, errs.ErrDBNotFound
+	}
+
+	return m.users[id], nil
+}
@@ -0,0 +1,153 @@
+package memory
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"time"
+
+	"CommentTree/internal/entities/comment"
+	"CommentTree/internal/entities/event"
+	"CommentTree/internal/entities/webhook"
+	"CommentTree/pkg/errs"
+)
+
+// enqueueWebhooks writes event typ about comment r to outbox for every
+// webhook which waits for it, m.mu should be locked.
+// Events about not approved comments aren't written.
+func (m *Memory) enqueueWebhooks(typ string, r *record) {
+	if r.Status != comment.StatusApproved {
+		return
+	}
+
+	p := webhook.Payload{
+		Event:      typ,
+		CommentID:  r.ID,
+		ParentID:   r.ParentID,
+		ThreadKey:  r.ThreadKey,
+		AuthorID:   r.AuthorID,
+		OccurredAt: now(),
+	}
+	if typ == event.TypeCreated {
+		p.Message = r.Message
+	}
+	// Payload has only plain fields, so it's always encoded.
+	payload, _ := json.Marshal(p)
+
+	for _, id := range slices.Sorted(maps.Keys(m.webhooks)) {
+		if !slices.Contains(m.webhooks[id].Events, typ) {
+			continue
+		}
+
+		m.lastDeliveryID++
+		m.outbox = append(m.outbox, &outboxRecord{
+			Delivery: webhook.Delivery{
+				ID:        m.lastDeliveryID,
+				WebhookID: id,
+				Event:     typ,
+				Payload:   payload,
+			},
+			nextAttemptAt: p.OccurredAt,
+		})
+	}
+}
+
+func (m *Memory) CreateWebhook(w webhook.Webhook) (webhook.Webhook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastWebhookID++
+	w.ID = m.lastWebhookID
+	w.CreatedAt = now()
+	m.webhooks[w.ID] = w
+
+	return w, nil
+}
+
+// Webhooks returns all webhooks without secrets.
+func (m *Memory) Webhooks() ([]webhook.Webhook, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	hooks := []webhook.Webhook{}
+	for _, id := range slices.Sorted(maps.Keys(m.webhooks)) {
+		w := m.webhooks[id]
+		w.Secret = ""
+		hooks = append(hooks, w)
+	}
+
+	return hooks, nil
+}
+
+// DeleteWebhook removes webhook with it's not sent deliveries.
+func (m *Memory) DeleteWebhook(id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.webhooks[id]; !ok {
+		return errs.ErrDBNotAffected
+	}
+
+	delete(m.webhooks, id)
+	m.outbox = slices.DeleteFunc(m.outbox, func(o *outboxRecord) bool {
+		return o.WebhookID == id
+	})
+
+	return nil
+}
+
+// ClaimDeliveries takes up to limit due deliveries and hides them from
+// other dispatchers for lease. Deliveries which were tried maxAttempts
+// times stay in outbox with their last error.
+func (m *Memory) ClaimDeliveries(limit int, lease time.Duration, maxAttempts int) ([]webhook.Delivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t := now()
+	deliveries := []webhook.Delivery{}
+	for _, o := range m.outbox {
+		if len(deliveries) == limit {
+			break
+		}
+		if o.nextAttemptAt.After(t) || o.Attempts >= maxAttempts {
+			continue
+		}
+
+		o.Attempts++
+		o.nextAttemptAt = t.Add(lease)
+
+		d := o.Delivery
+		d.URL = m.webhooks[o.WebhookID].URL
+		d.Secret = m.webhooks[o.WebhookID].Secret
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, nil
+}
+
+// CompleteDelivery removes delivered event from outbox.
+func (m *Memory) CompleteDelivery(id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.outbox = slices.DeleteFunc(m.outbox, func(o *outboxRecord) bool {
+		return o.ID == id
+	})
+
+	return nil
+}
+
+// FailDelivery saves error of delivery, it's tried again at retryAt.
+func (m *Memory) FailDelivery(id int64, reason string, retryAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, o := range m.outbox {
+		if o.ID == id {
+			o.lastError = reason
+			o.nextAttemptAt = retryAt
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,22 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+
+	"CommentTree/pkg/errs"
+)
+
+// SetStatus reports that comment is approved for the first time.
+func (s *Storage) SetStatus(id int64, status string) (bool, error) {
+	const op = "internal.storage.SetStatus"
+
+	created, err := s.db.SetStatus(id, status)
+	if errors.Is(err, errs.ErrDBNotAffected) {
+		return false, ErrNotAffected
+	} else if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return created, nil
+}
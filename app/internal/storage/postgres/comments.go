@@ -3,15 +3,122 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 
 	"CommentTree/internal/entities/comment"
+	"CommentTree/internal/entities/event"
 	"CommentTree/pkg/errs"
 
 	"github.com/wb-go/wbf/zlog"
 )
 
+// Columns of comments table in order of scanComment.
+var commentFields = []string{
+	"id", "message", "parent_id",
+	"deleted_at", "edited_at", "author_id", "created_at", "updated_at",
+	"thread_key", "status", "deleted_by",
+}
+
+// commentColumnsOf returns commentFields of table with alias.
+func commentColumnsOf(alias string) string {
+	cols := make([]string, 0, len(commentFields))
+	for _, f := range commentFields {
+		cols = append(cols, alias+"."+f)
+	}
+
+	return strings.Join(cols, ", ")
+}
+
+// Escapes wildcards of LIKE pattern, so they are searched as is.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// Counts of direct replies, all descendants and reactions,
+// used with comments table aliased as c. Only approved replies
+// are counted, so count is the same as readers see.
+var countColumns = fmt.Sprintf(`
+	(
+		select count(*) from %[1]s r
+		where r.parent_id = c.id and r.status = '%[5]s'
+	) as reply_count,
+	(
+		with recursive d as (
+			select id from %[1]s where parent_id = c.id and status = '%[5]s'
+			union all
+			select r.id from %[1]s r join d on r.parent_id = d.id
+			where r.status = '%[5]s'
+		)
+		select count(*) from d
+	) as descendant_count,
+	(
+		select coalesce(sum(case kind when '%[3]s' then 1 when '%[4]s' then -1 else 0 end), 0)
+		from %[2]s where comment_id = c.id
+	) as score,
+	(
+		select json_object_agg(kind, n) from (
+			select kind, count(*) as n from %[2]s
+			where comment_id = c.id group by kind
+		) k
+	) as reactions`,
+	CommentsTable, ReactionsTable, comment.ReactionUp, comment.ReactionDown,
+	comment.StatusApproved,
+)
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanComment scans commentFields, countColumns and then extra destinations.
+func scanComment(row scanner, extra ...any) (comment.Comment, error) {
+	var c comment.Comment
+	var parentID sql.NullInt64
+	var deletedAt sql.NullTime
+	var editedAt sql.NullTime
+	var authorID sql.NullInt64
+	var deletedBy sql.NullInt64
+	var reactions []byte
+
+	dest := append(
+		[]any{
+			&c.ID, &c.Message, &parentID,
+			&deletedAt, &editedAt, &authorID, &c.CreatedAt, &c.UpdatedAt,
+			&c.ThreadKey, &c.Status, &deletedBy, &c.ReplyCount, &c.DescendantCount, &c.Score, &reactions,
+		},
+		extra...,
+	)
+	if err := row.Scan(dest...); err != nil {
+		return comment.Comment{}, err
+	}
+
+	if parentID.Valid {
+		c.ParentID = parentID.Int64
+	}
+	if editedAt.Valid {
+		c.EditedAt = &editedAt.Time
+	}
+	if authorID.Valid {
+		c.AuthorID = authorID.Int64
+	}
+	if deletedBy.Valid {
+		c.DeletedBy = deletedBy.Int64
+	}
+	// NULL when comment has no reactions.
+	if len(reactions) > 0 {
+		if err := json.Unmarshal(reactions, &c.Reactions); err != nil {
+			return comment.Comment{}, err
+		}
+	}
+	// Tombstone keeps place in the tree, but not it's text.
+	if deletedAt.Valid {
+		c.Deleted = true
+		c.Message = comment.DeletedMessage
+	}
+
+	return c, nil
+}
+
 func (p *Postgres) issetComment(id int64) bool {
 	const op = "internal.storage.postgres.comments.issetComment"
 
@@ -20,12 +127,16 @@ func (p *Postgres) issetComment(id int64) bool {
 		return true
 	}
 
-	q := fmt.Sprintf("select * from %s where id = $1", CommentsTable)
+	// Can't reply to deleted or not approved comment.
+	q := fmt.Sprintf(
+		"select id from %s where id = $1 and deleted_at is null and status = $2",
+		CommentsTable,
+	)
 
-	var tmp comment.Comment
-	var nullPID sql.NullInt64
-	err := p.db.Master.QueryRowContext(context.Background(), q, id).
-		Scan(&tmp.ID, &tmp.Message, &nullPID)
+	var tmp int64
+	err := p.db.Master.QueryRowContext(
+		context.Background(), q, id, comment.StatusApproved,
+	).Scan(&tmp)
 	if errors.Is(err, sql.ErrNoRows) {
 		return false
 	} else if err != nil {
@@ -43,9 +154,18 @@ func (p *Postgres) CreateComment(c comment.Comment) (int64, error) {
 		return 0, errs.ErrDBViolatesForeignKey
 	}
 
-	q := fmt.Sprintf(
-		"insert into %s (message, parent_id) values ($1, $2) returning id;",
-		CommentsTable,
+	// Reply always belongs to thread of it's parent.
+	q := fmt.Sprintf(`
+		insert into %[1]s (
+			message, parent_id, author_id, search_vector, thread_key, status,
+			published
+		)
+		values (
+			$1, $2, $3, to_tsvector($4::regconfig, $1),
+			coalesce((select thread_key from %[1]s where id = $2), $5), $6,
+			$6 = '%[2]s'
+		) returning id;`,
+		CommentsTable, comment.StatusApproved,
 	)
 
 	// if we don't have parent id, we will insert NULL to db
@@ -53,15 +173,43 @@ func (p *Postgres) CreateComment(c comment.Comment) (int64, error) {
 	parentIDArg := sql.NullInt64{
 		Int64: c.ParentID, Valid: haveParentID,
 	}
+	status := c.Status
+	if status == "" {
+		status = comment.StatusApproved
+	}
+	// Same for anonymous author.
+	authorIDArg := sql.NullInt64{
+		Int64: c.AuthorID, Valid: c.AuthorID > 0,
+	}
+
+	ctx := context.Background()
+	tx, err := p.db.Master.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
 
 	var id int64
-	err := p.db.Master.QueryRowContext(
-		context.Background(), q, c.Message, parentIDArg,
+	err = tx.QueryRowContext(
+		ctx, q,
+		c.Message, parentIDArg, authorIDArg, p.lang, c.ThreadKey, status,
 	).Scan(&id)
 	if err != nil {
 		return 0, p.unwrapInternalError(op, err)
 	}
 
+	// Pending comment is sent to webhooks after approve.
+	if err := enqueueWebhooks(ctx, tx, event.TypeCreated, id); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
 	return id, nil
 }
 
@@ -72,17 +220,16 @@ func (p *Postgres) generateAgrigatedOpts(parentID int64, opts *comment.GetterOpt
 
 	// Initialization values.
 	args := []any{}
-	argsStr := fmt.Sprintf(`
-		select * from %s
-	`, CommentsTable)
+	conds := []string{}
+	// Rank and snippet have sense only for fulltext search,
+	// similarity only for fuzzy.
+	searchCols := "0::real as rank, ''::text as snippet, 0::real as similarity"
 
 	argIDX := 1
-	var addArg bool
 	if parentID > 0 {
-		argsStr += fmt.Sprintf(" where parent_id = $%d", argIDX)
+		conds = append(conds, fmt.Sprintf("parent_id = $%d", argIDX))
 		args = append(args, parentID)
 		argIDX++
-		addArg = true
 	} else if !opts.SearchGlobal {
 		/*
 			If we want to search for substr in all comments,
@@ -92,29 +239,128 @@ func (p *Postgres) generateAgrigatedOpts(parentID int64, opts *comment.GetterOpt
 			Now, when ParentID == 0 and the SearchGlobal flag is set,
 			it will not add filters for id.
 		*/
-		argsStr += " where parent_id is NULL"
-		addArg = true
+		conds = append(conds, "parent_id is NULL")
 	}
 
-	if opts.Substr != "" {
-		// If we didn't set id, we wouldn't add 'and'.
-		if addArg {
-			argsStr += " and"
-		} else {
-			argsStr += " where"
+	if !opts.AnyStatus {
+		status := opts.Status
+		if status == "" {
+			status = comment.StatusApproved
 		}
+		conds = append(conds, fmt.Sprintf("status = $%d", argIDX))
+		args = append(args, status)
+		argIDX++
+	}
+
+	if opts.ThreadKey != "" {
+		conds = append(conds, fmt.Sprintf("thread_key = $%d", argIDX))
+		args = append(args, opts.ThreadKey)
+		argIDX++
+	}
 
-		argsStr += fmt.Sprintf(" POSITION($%d IN message) > 0", argIDX)
+	// Text of deleted comments is hidden, so we don't search in it.
+	if opts.Substr != "" && opts.SearchMode == comment.SearchFulltext {
+		query := fmt.Sprintf(
+			"websearch_to_tsquery($%d::regconfig, $%d)", argIDX, argIDX+1,
+		)
+		conds = append(conds, fmt.Sprintf(
+			"search_vector @@ %s and deleted_at is NULL", query,
+		))
+		searchCols = fmt.Sprintf(
+			"ts_rank(c.search_vector, %[1]s) as rank, "+
+				"ts_headline($%[2]d::regconfig, c.message, %[1]s) as snippet, "+
+				"0::real as similarity",
+			query, argIDX,
+		)
+		args = append(args, p.lang, opts.Substr)
+		argIDX += 2
+	} else if opts.Substr != "" && opts.SearchMode == comment.SearchFuzzy {
+		/*
+			ILIKE finds exact substring in any case, word similarity
+			finds close words with typos, both use trigram index.
+		*/
+		conds = append(conds, fmt.Sprintf(
+			"(message ILIKE $%[1]d or $%[2]d <%% message) and deleted_at is NULL",
+			argIDX, argIDX+1,
+		))
+		searchCols = fmt.Sprintf(
+			"0::real as rank, ''::text as snippet, "+
+				"word_similarity($%d, c.message) as similarity",
+			argIDX+1,
+		)
+		args = append(args, "%"+likeEscaper.Replace(opts.Substr)+"%", opts.Substr)
+		argIDX += 2
+	} else if opts.Substr != "" {
+		conds = append(conds, fmt.Sprintf(
+			"POSITION($%d IN message) > 0 and deleted_at is NULL", argIDX,
+		))
 		args = append(args, opts.Substr)
+		argIDX++
+	}
+
+	argsStr := fmt.Sprintf(
+		"select %s, %s, %s from %s c",
+		commentColumnsOf("c"), countColumns, searchCols, CommentsTable,
+	)
+	if len(conds) > 0 {
+		argsStr += " where " + strings.Join(conds, " and ")
 	}
 
-	if opts.Page > 0 {
+	/*
+		Sorting by computed columns is done in outer query,
+		so cursor conditions can use them like usual columns.
+	*/
+	argsStr = fmt.Sprintf("select * from (%s) s", argsStr)
+
+	var order, cursorCond string
+	var cursorArgs []any
+	switch {
+	case opts.SearchMode == comment.SearchFulltext && opts.Substr != "":
+		// Most relevant first, fulltext search is paged only by page.
+		order = "rank desc, id desc"
+	case opts.SearchMode == comment.SearchFuzzy && opts.Substr != "":
+		order = "similarity desc, id desc"
+	case opts.Sort == comment.SortNewest:
+		order = "created_at desc, id desc"
+		cursorCond = "(created_at, id) < ($%d, $%d)"
+	case opts.Sort == comment.SortMostReplied:
+		order = "reply_count desc, id desc"
+		cursorCond = "(reply_count, id) < ($%d, $%d)"
+	case opts.Sort == comment.SortTop:
+		order = "score desc, id desc"
+		cursorCond = "(score, id) < ($%d, $%d)"
+	default:
+		order = "created_at, id"
+		cursorCond = "(created_at, id) > ($%d, $%d)"
+	}
+
+	if opts.After != nil && cursorCond != "" {
+		if opts.Sort == comment.SortMostReplied || opts.Sort == comment.SortTop {
+			cursorArgs = []any{opts.After.Rank, opts.After.ID}
+		} else {
+			cursorArgs = []any{opts.After.CreatedAt, opts.After.ID}
+		}
+
+		argsStr += " where " + fmt.Sprintf(cursorCond, argIDX, argIDX+1)
+		args = append(args, cursorArgs...)
+	}
+
+	argsStr += " order by " + order
+
+	// One more row shows that we have next page.
+	limit := comment.PageElements + 1
+	if opts.After != nil && cursorCond != "" {
+		argsStr += fmt.Sprintf(" limit %d", limit)
+		return args, argsStr
+	}
+
+	page := opts.Page
+	if page > 0 {
 		// 1(PageElement) * 1(opts.Page is first) = 1, wrong offset for first page.
-		opts.Page--
+		page--
 	}
 
-	limit := comment.PageElements
-	offset := comment.PageElements * opts.Page
+	offset := comment.PageElements * page
 	argsStr += fmt.Sprintf(" limit %d offset %d", limit, offset)
 
 	return args, argsStr
@@ -123,67 +369,94 @@ func (p *Postgres) generateAgrigatedOpts(parentID int64, opts *comment.GetterOpt
 func (p *Postgres) Parent(id int64) (comment.Comment, error) {
 	const op = "internal.storage.postgres.comments.parent"
 
-	qParent := fmt.Sprintf(`select * from %s where id = $1`, CommentsTable)
-
-	var parent comment.Comment
-	var nullID sql.NullInt64
-
-	err := p.db.Master.QueryRowContext(context.Background(), qParent, id).
-		Scan(&parent.ID, &parent.Message, &nullID)
+	qParent := fmt.Sprintf(
+		`select %s, %s from %s c where id = $1`,
+		commentColumnsOf("c"), countColumns, CommentsTable,
+	)
 
+	row := p.db.Master.QueryRowContext(context.Background(), qParent, id)
+	parent, err := scanComment(row)
 	if errors.Is(err, sql.ErrNoRows) {
 		return comment.Comment{}, errs.ErrDBNotFound
 	} else if err != nil {
 		return comment.Comment{}, fmt.Errorf("%s: %w", op, err)
 	}
 
-	if nullID.Valid {
-		parent.ParentID = nullID.Int64
-	}
-
 	return parent, nil
 }
 
-func (p *Postgres) lastID(parentID int64) int64 {
-	const op = "internal.storage.postgres.lastID"
+func (p *Postgres) Single(id int64, anyStatus bool) (comment.SingleView, error) {
+	// Path ends with the comment, so it's read once.
+	path, err := p.Path(id, anyStatus)
+	if err != nil {
+		return comment.SingleView{}, err
+	}
 
-	args := make([]any, 0, 1)
+	return comment.SingleView{
+		Comment: path[len(path)-1],
+		RootID:  path[0].ID,
+		Depth:   int64(len(path) - 1),
+	}, nil
+}
 
+// Path returns chain of comments from the root, without anyStatus
+// it isn't found if some comment of chain isn't approved.
+func (p *Postgres) Path(id int64, anyStatus bool) ([]comment.Comment, error) {
+	const op = "internal.storage.postgres.comments.path"
+
+	// Walk up by parent_id, the last found ancestor is the root.
 	q := fmt.Sprintf(`
-		select id from %s where parent_id`,
-		CommentsTable,
+		with recursive ancestors as (
+			select id, parent_id, 0 as depth from %[1]s where id = $1
+			union all
+			select c.id, c.parent_id, a.depth + 1
+			from %[1]s c join ancestors a on c.id = a.parent_id
+		)
+		select %[2]s, %[3]s
+		from ancestors a join %[1]s c on c.id = a.id
+		order by a.depth desc;`,
+		CommentsTable, commentColumnsOf("c"), countColumns,
 	)
 
-	// Because we can load more from root comments and from child comments.
-	if parentID == 0 {
-		q += " is null"
-	} else {
-		q += "=$1"
-		args = append(args, parentID)
+	rows, err := p.queryMaster(context.Background(), q, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	q += ` order by id desc limit 1;`
+	defer func() {
+		_ = rows.Close()
+	}()
 
-	var id int64
-	err := p.db.Master.QueryRowContext(
-		context.Background(), q, args...,
-	).Scan(&id)
-	if err != nil && !errors.Is(err, sql.ErrNoRows) {
-		zlog.Logger.Error().Err(fmt.Errorf("%s: %w", op, err)).Send()
-		return 0
+	path := []comment.Comment{}
+	for rows.Next() {
+		tmp, err := scanComment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		// Readers see neither not approved comment nor it's replies.
+		if !anyStatus && !tmp.Public() {
+			return nil, errs.ErrDBNotFound
+		}
+
+		path = append(path, tmp)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(path) == 0 {
+		return nil, errs.ErrDBNotFound
 	}
 
-	return id
+	return path, nil
 }
 
 func (p *Postgres) Childs(parentID int64, opts *comment.GetterOpts) ([]comment.Comment, error) {
 	const op = "internal.storage.postgres.comments.childs"
 
 	args, q := p.generateAgrigatedOpts(parentID, opts)
-	rows, err := p.db.QueryWithRetry(
-		context.Background(), retryOpts,
-		q, args...,
-	)
+	rows, err := p.queryReplica(context.Background(), q, args...)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
@@ -192,47 +465,266 @@ func (p *Postgres) Childs(parentID int64, opts *comment.GetterOpts) ([]comment.C
 		_ = rows.Close()
 	}()
 
-	if errors.Is(rows.Err(), sql.ErrNoRows) {
-		return nil, errs.ErrDBNotFound
-	} else if rows.Err() != nil {
+	coms := []comment.Comment{}
+	for rows.Next() {
+		var rank, similarity float64
+		var snippet string
+		tmp, err := scanComment(rows, &rank, &snippet, &similarity)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		tmp.Rank = rank
+		tmp.Snippet = snippet
+		tmp.Similarity = similarity
+
+		// Every comment except the last one have next.
+		tmp.HaveNext = true
+		coms = append(coms, tmp)
+	}
+	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	lastID := p.lastID(parentID)
-	coms := []comment.Comment{}
-	for i := 0; rows.Next(); i++ {
-		var tmp comment.Comment
-		var parentID sql.NullInt64
+	// Extra row only shows that we have next page.
+	if len(coms) > comment.PageElements {
+		coms = coms[:comment.PageElements]
+	} else if len(coms) > 0 {
+		coms[len(coms)-1].HaveNext = false
+	}
 
-		err := rows.Scan(&tmp.ID, &tmp.Message, &parentID)
+	return coms, nil
+}
+
+func (p *Postgres) Tree(id int64, opts *comment.TreeOpts) (*comment.TreeNode, error) {
+	const op = "internal.storage.postgres.comments.tree"
+
+	// Readers don't see tree under not approved comment or ancestor.
+	if !opts.AnyStatus {
+		if _, err := p.Path(id, false); err != nil {
+			return nil, err
+		}
+	}
+
+	/*
+		Every node loads limit+1 replies, so we know that node have more
+		replies than limit, but only first limit replies go deeper.
+	*/
+	q := fmt.Sprintf(`
+		with recursive tree as (
+			select id, parent_id, 0 as depth, 1::bigint as rn
+			from %[1]s where id = $1
+			union all
+			select ch.id, ch.parent_id, t.depth + 1, ch.rn
+			from tree t cross join lateral (
+				select c.id, c.parent_id,
+					row_number() over (order by c.created_at, c.id) as rn
+				from %[1]s c where c.parent_id = t.id and c.status = $5
+				order by c.created_at, c.id limit $4
+			) ch
+			where t.depth < $2 and t.rn <= $3
+		)
+		select %[2]s, %[3]s, t.rn
+		from tree t join %[1]s c on c.id = t.id
+		order by t.depth, t.parent_id, t.rn;`,
+		CommentsTable, commentColumnsOf("c"), countColumns,
+	)
+
+	rows, err := p.queryMaster(
+		context.Background(),
+		q, id, opts.MaxDepth, opts.Limit, opts.Limit+1, comment.StatusApproved,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var root *comment.TreeNode
+	nodes := make(map[int64]*comment.TreeNode)
+	for rows.Next() {
+		var rn int
+		c, err := scanComment(rows, &rn)
 		if err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
+		node := comment.TreeNode{Comment: c}
 
-		if parentID.Valid {
-			tmp.ParentID = parentID.Int64
+		if root == nil {
+			node.Replies = []*comment.TreeNode{}
+			root = &node
+			nodes[node.ID] = root
+			continue
 		}
-		if tmp.ID == lastID {
-			tmp.HaveNext = false
-		} else {
-			tmp.HaveNext = true
+
+		parent, ok := nodes[node.ParentID]
+		if !ok {
+			continue
+		}
+		// Extra reply only marks that parent have more replies.
+		if rn > opts.Limit {
+			parent.Replies[len(parent.Replies)-1].HaveNext = true
+			continue
 		}
 
-		coms = append(coms, tmp)
+		node.Replies = []*comment.TreeNode{}
+		nodes[node.ID] = &node
+		parent.Replies = append(parent.Replies, &node)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	// If we have load more button after last comment.
-	if lastID == 0 && len(coms) > 0 {
-		coms[len(coms)-1].HaveNext = false
+	if root == nil {
+		return nil, errs.ErrDBNotFound
 	}
 
-	return coms, nil
+	return root, nil
+}
+
+// EditComment saves current message of comment to revisions
+// and replaces it with new one, status isn't changed if it's empty.
+func (p *Postgres) EditComment(id int64, message, status string) error {
+	const op = "internal.storage.postgres.comments.Edit"
+
+	q := fmt.Sprintf(`
+		with old as (
+			select id, message from %[1]s
+			where id = $1 and deleted_at is null for update
+		), revision as (
+			insert into %[2]s (comment_id, message) select id, message from old
+		)
+		update %[1]s c set message = $2, edited_at = now(), updated_at = now(),
+			search_vector = to_tsvector($3::regconfig, $2),
+			status = coalesce(nullif($4, ''), c.status)
+		from old where c.id = old.id;`,
+		CommentsTable, RevisionsTable,
+	)
+
+	res, err := p.db.ExecContext(context.Background(), q, id, message, p.lang, status)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	} else if affected == 0 {
+		return errs.ErrDBNotAffected
+	}
+
+	return nil
+}
+
+func (p *Postgres) Revisions(id int64, anyStatus bool) ([]comment.Revision, error) {
+	const op = "internal.storage.postgres.comments.revisions"
+
+	// Path isn't found if comment or it's ancestor is hidden from readers.
+	path, err := p.Path(id, anyStatus)
+	if err != nil {
+		return nil, err
+	}
+	c := path[len(path)-1]
+
+	revs := []comment.Revision{}
+	// History of deleted comment is hidden as it's message.
+	if c.Deleted {
+		return revs, nil
+	}
+
+	q := fmt.Sprintf(`
+		select id, comment_id, message, created_at from %s
+		where comment_id = $1 order by created_at, id;`,
+		RevisionsTable,
+	)
+
+	rows, err := p.queryMaster(context.Background(), q, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		var rev comment.Revision
+
+		err := rows.Scan(&rev.ID, &rev.CommentID, &rev.Message, &rev.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		revs = append(revs, rev)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return revs, nil
 }
 
-func (p *Postgres) DeleteComment(id int64) error {
+// DeleteComment marks comment as deleted by user and keeps it's replies,
+// with cascade it removes comment with all replies from table.
+func (p *Postgres) DeleteComment(id, userID int64, cascade bool) error {
 	const op = "internal.storage.postgres.comments.Delete"
 
-	q := fmt.Sprintf("delete from %s where id = $1", CommentsTable)
+	q := fmt.Sprintf(
+		`update %s set deleted_at = now(), updated_at = now(), deleted_by = $2
+		where id = $1 and deleted_at is null`,
+		CommentsTable,
+	)
+	userIDArg := sql.NullInt64{
+		Int64: userID, Valid: userID > 0,
+	}
+	args := []any{id, userIDArg}
+	if cascade {
+		q = fmt.Sprintf("delete from %s where id = $1", CommentsTable)
+		args = args[:1]
+	}
+
+	ctx := context.Background()
+	tx, err := p.db.Master.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	// Event is written before delete, when comment is still in table,
+	// it's rolled back if nothing was deleted.
+	if err := enqueueWebhooks(ctx, tx, event.TypeDeleted, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := tx.ExecContext(ctx, q, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	} else if affected == 0 {
+		return errs.ErrDBNotAffected
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (p *Postgres) RestoreComment(id int64) error {
+	const op = "internal.storage.postgres.comments.Restore"
+
+	q := fmt.Sprintf(
+		`update %s set deleted_at = NULL, deleted_by = NULL, updated_at = now()
+		where id = $1 and deleted_at is not null`,
+		CommentsTable,
+	)
 
 	res, err := p.db.ExecContext(context.Background(), q, id)
 	if err != nil {
@@ -0,0 +1,291 @@
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	MigrationsTable = "schema_migrations"
+
+	// Key of advisory lock, only one instance migrates at once.
+	MigrationsLock = 0x636f6d6d656e74
+
+	// Existing comments table without migrations table is the original
+	// schema of 0001, it's marked as applied and gets all later migrations.
+	BaselineVersion = 1
+
+	// Setting with text search language of app, migrations which
+	// fill search_vector read it by current_setting.
+	SearchLanguageSetting = "comments.search_language"
+)
+
+var ErrWrongMigration = errors.New("wrong migration")
+
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+type migration struct {
+	version int64
+	name    string
+	up      string
+	down    string
+}
+
+// loadMigrations reads pairs of files like 0001_name.up.sql
+// and 0001_name.down.sql, migrations are sorted by version.
+func loadMigrations(fsys fs.FS) ([]migration, error) {
+	files, err := fs.Glob(fsys, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*migration)
+	for _, file := range files {
+		base := path.Base(file)
+
+		name, direction, ok := strings.Cut(strings.TrimSuffix(base, ".sql"), ".")
+		prefix, title, found := strings.Cut(name, "_")
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if !ok || !found || err != nil || version <= 0 {
+			return nil, fmt.Errorf("%w: %s", ErrWrongMigration, base)
+		}
+
+		q, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &migration{version: version, name: title}
+			byVersion[version] = m
+		} else if m.name != title {
+			return nil, fmt.Errorf("%w: two names of version %d", ErrWrongMigration, version)
+		}
+
+		switch direction {
+		case "up":
+			m.up = string(q)
+		case "down":
+			m.down = string(q)
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrWrongMigration, base)
+		}
+	}
+
+	res := make([]migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.up == "" || m.down == "" {
+			return nil, fmt.Errorf(
+				"%w: version %d needs up and down", ErrWrongMigration, m.version,
+			)
+		}
+		res = append(res, *m)
+	}
+	slices.SortFunc(res, func(a, b migration) int {
+		return cmp.Compare(a.version, b.version)
+	})
+
+	return res, nil
+}
+
+// Migrate applies migrations which aren't applied yet.
+func (p *Postgres) Migrate() error {
+	const op = "internal.storage.postgres.Migrate"
+
+	migrations, err := loadMigrations(migrationsFS)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = p.withMigrationsLock(func(ctx context.Context, conn *sql.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range migrations {
+			if slices.Contains(applied, m.version) {
+				continue
+			}
+
+			q := fmt.Sprintf(
+				"insert into %s (version, name) values ($1, $2)", MigrationsTable,
+			)
+			err := p.inTx(ctx, conn, m.up, q, m.version, m.name)
+			if err != nil {
+				return fmt.Errorf("%d_%s: %w", m.version, m.name, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Rollback reverts last steps applied migrations.
+func (p *Postgres) Rollback(steps int) error {
+	const op = "internal.storage.postgres.Rollback"
+
+	migrations, err := loadMigrations(migrationsFS)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = p.withMigrationsLock(func(ctx context.Context, conn *sql.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		slices.Reverse(applied)
+		for _, version := range applied[:min(steps, len(applied))] {
+			i := slices.IndexFunc(migrations, func(m migration) bool {
+				return m.version == version
+			})
+			if i < 0 {
+				return fmt.Errorf("%w: unknown version %d", ErrWrongMigration, version)
+			}
+			m := migrations[i]
+
+			q := fmt.Sprintf("delete from %s where version = $1", MigrationsTable)
+			err := p.inTx(ctx, conn, m.down, q, m.version)
+			if err != nil {
+				return fmt.Errorf("%d_%s: %w", m.version, m.name, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// withMigrationsLock runs fn on connection which holds advisory lock,
+// so instances which start together don't migrate twice.
+func (p *Postgres) withMigrationsLock(fn func(ctx context.Context, conn *sql.Conn) error) error {
+	ctx := context.Background()
+	conn, err := p.db.Master.Conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	if _, err := conn.ExecContext(ctx, "select pg_advisory_lock($1)", MigrationsLock); err != nil {
+		return err
+	}
+
+	defer func() {
+		_, _ = conn.ExecContext(ctx, "select pg_advisory_unlock($1)", MigrationsLock)
+	}()
+
+	if err := p.createMigrationsTable(ctx, conn); err != nil {
+		return err
+	}
+
+	return fn(ctx, conn)
+}
+
+// createMigrationsTable creates table of applied versions, existing
+// comments table is marked as BaselineVersion.
+func (p *Postgres) createMigrationsTable(ctx context.Context, conn *sql.Conn) error {
+	var exists, baseline bool
+	err := conn.QueryRowContext(
+		ctx, "select to_regclass($1) is not null, to_regclass($2) is not null",
+		MigrationsTable, CommentsTable,
+	).Scan(&exists, &baseline)
+	if err != nil || exists {
+		return err
+	}
+
+	q := fmt.Sprintf(`
+		create table %s (
+			version bigint primary key,
+			name text not null,
+			applied_at timestamptz not null default now()
+		);`,
+		MigrationsTable,
+	)
+	if !baseline {
+		_, err := conn.ExecContext(ctx, q)
+		return err
+	}
+
+	qBaseline := fmt.Sprintf(
+		"insert into %s (version, name) values ($1, 'baseline')", MigrationsTable,
+	)
+	return p.inTx(ctx, conn, q, qBaseline, BaselineVersion)
+}
+
+// appliedVersions returns versions of applied migrations in ascending order.
+func appliedVersions(ctx context.Context, conn *sql.Conn) ([]int64, error) {
+	q := fmt.Sprintf("select version from %s order by version", MigrationsTable)
+
+	rows, err := conn.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	versions := []int64{}
+	for rows.Next() {
+		var v int64
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+
+		versions = append(versions, v)
+	}
+
+	return versions, rows.Err()
+}
+
+// inTx runs script and then bookkeeping query q with args in one transaction,
+// script sees search language of p in SearchLanguageSetting.
+func (p *Postgres) inTx(ctx context.Context, conn *sql.Conn, script, q string, args ...any) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	_, err = tx.ExecContext(ctx, "select set_config($1, $2, true)", SearchLanguageSetting, p.lang)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, q, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
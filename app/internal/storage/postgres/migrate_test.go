@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	file := func(q string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte(q)}
+	}
+
+	tests := []struct {
+		name    string // description of this test case
+		fsys    fstest.MapFS
+		want    []int64
+		wantErr error
+	}{
+		{
+			name: "sorted by version",
+			fsys: fstest.MapFS{
+				"migrations/0010_b.up.sql":   file("up b"),
+				"migrations/0010_b.down.sql": file("down b"),
+				"migrations/0002_a.up.sql":   file("up a"),
+				"migrations/0002_a.down.sql": file("down a"),
+			},
+			want: []int64{2, 10},
+		},
+		{
+			name: "without down",
+			fsys: fstest.MapFS{
+				"migrations/0001_a.up.sql": file("up a"),
+			},
+			wantErr: ErrWrongMigration,
+		},
+		{
+			name: "without version",
+			fsys: fstest.MapFS{
+				"migrations/init.up.sql":   file("up"),
+				"migrations/init.down.sql": file("down"),
+			},
+			wantErr: ErrWrongMigration,
+		},
+		{
+			name: "unknown direction",
+			fsys: fstest.MapFS{
+				"migrations/0001_a.sideways.sql": file("up a"),
+			},
+			wantErr: ErrWrongMigration,
+		},
+		{
+			name: "two names of version",
+			fsys: fstest.MapFS{
+				"migrations/0001_a.up.sql":   file("up a"),
+				"migrations/0001_b.down.sql": file("down b"),
+			},
+			wantErr: ErrWrongMigration,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := loadMigrations(tt.fsys)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("loadMigrations() err = %v, want %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("loadMigrations() = %v, want versions %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i].version != tt.want[i] || got[i].up == "" || got[i].down == "" {
+					t.Errorf("loadMigrations()[%d] = %+v, want version %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLoadMigrations_Embedded(t *testing.T) {
+	got, err := loadMigrations(migrationsFS)
+	if err != nil {
+		t.Fatalf("loadMigrations() err = %v", err)
+	}
+	if len(got) == 0 || got[0].version != BaselineVersion {
+		t.Errorf("loadMigrations() = %v, want baseline first", got)
+	}
+}
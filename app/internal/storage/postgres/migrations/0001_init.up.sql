@@ -4,4 +4,4 @@ create table comments (
     parent_id bigint null,
 
     foreign key (parent_id) references comments(id) on delete cascade
-);
\ No newline at end of file
+);
@@ -0,0 +1 @@
+alter table comments add column if not exists deleted_at timestamptz null;
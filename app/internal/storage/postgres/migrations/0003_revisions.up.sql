@@ -0,0 +1,12 @@
+alter table comments add column if not exists edited_at timestamptz null;
+
+create table if not exists comment_revisions (
+    id serial primary key,
+    comment_id bigint not null,
+    message text not null,
+    created_at timestamptz not null default now(),
+
+    foreign key (comment_id) references comments(id) on delete cascade
+);
+
+create index if not exists comment_revisions_comment_id_idx on comment_revisions (comment_id);
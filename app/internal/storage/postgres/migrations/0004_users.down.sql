@@ -0,0 +1,2 @@
+alter table comments drop column if exists author_id;
+drop table if exists users;
@@ -0,0 +1,10 @@
+create table if not exists users (
+    id serial primary key,
+    name text not null unique,
+    token_hash text not null unique,
+    role text not null default 'user',
+    created_at timestamptz not null default now()
+);
+
+alter table comments add column if not exists author_id bigint null
+    references users(id) on delete set null;
@@ -0,0 +1,2 @@
+drop index if exists comments_parent_created_idx;
+alter table comments drop column if exists created_at;
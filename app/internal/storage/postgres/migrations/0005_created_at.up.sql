@@ -0,0 +1,3 @@
+alter table comments add column if not exists created_at timestamptz not null default now();
+
+create index if not exists comments_parent_created_idx on comments (parent_id, created_at, id);
@@ -0,0 +1 @@
+alter table comments drop column if exists updated_at;
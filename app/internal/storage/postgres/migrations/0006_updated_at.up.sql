@@ -0,0 +1 @@
+alter table comments add column if not exists updated_at timestamptz not null default now();
@@ -0,0 +1,2 @@
+drop index if exists comments_search_idx;
+alter table comments drop column if exists search_vector;
@@ -0,0 +1,13 @@
+-- Filled by app with configured text search language.
+alter table comments add column if not exists search_vector tsvector;
+
+-- Existing comments get language of app which runs migration,
+-- changing it later requires rebuilding of column.
+update comments
+set search_vector = to_tsvector(
+    coalesce(nullif(current_setting('comments.search_language', true), ''), 'english')::regconfig,
+    message
+)
+where search_vector is null;
+
+create index if not exists comments_search_idx on comments using gin (search_vector);
@@ -0,0 +1 @@
+drop index if exists comments_message_trgm_idx;
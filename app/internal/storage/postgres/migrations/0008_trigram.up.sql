@@ -0,0 +1,3 @@
+create extension if not exists pg_trgm;
+
+create index if not exists comments_message_trgm_idx on comments using gin (message gin_trgm_ops);
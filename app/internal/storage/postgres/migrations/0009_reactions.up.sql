@@ -0,0 +1,11 @@
+create table if not exists reactions (
+    comment_id bigint not null,
+    user_id bigint not null,
+    -- up, down or emoji.
+    kind text not null,
+    created_at timestamptz not null default now(),
+
+    primary key (comment_id, user_id, kind),
+    foreign key (comment_id) references comments(id) on delete cascade,
+    foreign key (user_id) references users(id) on delete cascade
+);
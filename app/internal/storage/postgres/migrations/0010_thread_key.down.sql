@@ -0,0 +1,2 @@
+drop index if exists comments_thread_created_idx;
+alter table comments drop column if exists thread_key;
@@ -0,0 +1,4 @@
+-- Identifier of external resource, like article or product.
+alter table comments add column if not exists thread_key text not null default '';
+
+create index if not exists comments_thread_created_idx on comments (thread_key, parent_id, created_at, id);
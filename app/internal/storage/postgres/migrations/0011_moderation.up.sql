@@ -0,0 +1,4 @@
+-- pending, approved or rejected.
+alter table comments add column if not exists status text not null default 'approved';
+
+create index if not exists comments_status_created_idx on comments (status, created_at, id);
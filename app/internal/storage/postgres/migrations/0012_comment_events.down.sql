@@ -0,0 +1 @@
+drop sequence if exists comment_events_id_seq;
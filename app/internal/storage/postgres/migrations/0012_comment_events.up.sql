@@ -0,0 +1,2 @@
+-- Ids of events sent by NOTIFY, same on all app instances.
+create sequence if not exists comment_events_id_seq;
@@ -0,0 +1,2 @@
+drop table if exists webhook_outbox;
+drop table if exists webhooks;
@@ -0,0 +1,28 @@
+create table if not exists webhooks (
+    id serial primary key,
+    url text not null,
+    -- Key of HMAC signature, it's needed in plain form.
+    secret text not null,
+    -- Types of sent events, created or deleted.
+    events text[] not null,
+    created_at timestamptz not null default now()
+);
+
+-- Events for webhooks, written in the same transaction as comment change.
+create table if not exists webhook_outbox (
+    id bigserial primary key,
+    webhook_id bigint not null,
+    event text not null,
+    payload jsonb not null,
+    attempts int not null default 0,
+    -- Time of next try, it's moved forward while delivery is sent.
+    next_attempt_at timestamptz not null default now(),
+    delivered_at timestamptz null,
+    last_error text null,
+    created_at timestamptz not null default now(),
+
+    foreign key (webhook_id) references webhooks(id) on delete cascade
+);
+
+create index if not exists webhook_outbox_pending_idx on webhook_outbox (next_attempt_at)
+    where delivered_at is null;
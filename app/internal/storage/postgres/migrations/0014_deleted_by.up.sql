@@ -0,0 +1,3 @@
+-- User who deleted comment, author can't restore deletion of moderator.
+alter table comments add column if not exists deleted_by bigint null
+    references users(id) on delete set null;
@@ -0,0 +1,7 @@
+-- Comment was approved once, so readers and webhooks already got it.
+alter table comments add column if not exists published boolean not null default false;
+
+-- Not approved comment with revisions most likely was sent back by edit.
+update comments c set published = true
+where c.status = 'approved'
+    or exists (select 1 from comment_revisions r where r.comment_id = c.id);
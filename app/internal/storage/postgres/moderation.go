@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"CommentTree/internal/entities/comment"
+	"CommentTree/internal/entities/event"
+	"CommentTree/pkg/errs"
+)
+
+// SetStatus changes moderation status of comment, comment
+// which already has this status isn't affected. It reports that comment
+// is approved for the first time, so it's created for readers.
+func (p *Postgres) SetStatus(id int64, status string) (bool, error) {
+	const op = "internal.storage.postgres.moderation.SetStatus"
+
+	qPublished := fmt.Sprintf(
+		"select published from %s where id = $1 for update", CommentsTable,
+	)
+	q := fmt.Sprintf(
+		`update %s set status = $2, updated_at = now(),
+			published = published or $2 = '%s'
+		where id = $1 and status <> $2`,
+		CommentsTable, comment.StatusApproved,
+	)
+
+	ctx := context.Background()
+	tx, err := p.db.Master.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var published bool
+	err = tx.QueryRowContext(ctx, qPublished, id).Scan(&published)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, errs.ErrDBNotAffected
+	} else if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := tx.ExecContext(ctx, q, id, status)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected, err := res.RowsAffected(); err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	} else if affected == 0 {
+		return false, errs.ErrDBNotAffected
+	}
+
+	// For webhooks approved comment is just created, edits
+	// aren't sent to them.
+	created := status == comment.StatusApproved && !published
+	if created {
+		if err := enqueueWebhooks(ctx, tx, event.TypeCreated, id); err != nil {
+			return false, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return created, nil
+}
@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"CommentTree/internal/entities/event"
+
+	"github.com/lib/pq"
+	"github.com/wb-go/wbf/zlog"
+)
+
+const (
+	// Channel of comment events for all app instances.
+	EventsChannel = "comment_events"
+	// Sequence of ids of events.
+	EventsSequence = "comment_events_id_seq"
+
+	// Payload of NOTIFY should be less than 8000 bytes,
+	// bigger events are sent without comment.
+	MaxNotifyPayload = 7900
+
+	// Reconnect intervals of listener.
+	MinReconnectInterval = time.Second
+	MaxReconnectInterval = time.Minute
+)
+
+// Notifier sends events with NOTIFY and receives events
+// of every instance with LISTEN.
+type Notifier struct {
+	p *Postgres
+	l *pq.Listener
+}
+
+func NewNotifier(conn string, p *Postgres) *Notifier {
+	const op = "internal.storage.postgres.notifier.listener"
+
+	l := pq.NewListener(
+		conn, MinReconnectInterval, MaxReconnectInterval,
+		func(ev pq.ListenerEventType, err error) {
+			if err != nil {
+				zlog.Logger.Error().Err(fmt.Errorf("%s: %w", op, err)).Send()
+			}
+		},
+	)
+
+	if err := l.Listen(EventsChannel); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	return &Notifier{
+		p: p,
+		l: l,
+	}
+}
+
+// Publish sends e to all instances, id of e is taken from sequence.
+func (n *Notifier) Publish(e event.Event) {
+	const op = "internal.storage.postgres.notifier.Publish"
+
+	payload, err := notifyPayload(e)
+	if err != nil {
+		zlog.Logger.Error().Err(fmt.Errorf("%s: %w", op, err)).Send()
+		return
+	}
+
+	q := fmt.Sprintf(
+		`select pg_notify($1, jsonb_set($2::jsonb, '{id}', to_jsonb(nextval('%s')))::text)`,
+		EventsSequence,
+	)
+
+	_, err = n.p.db.ExecContext(context.Background(), q, EventsChannel, string(payload))
+	if err != nil {
+		zlog.Logger.Error().Err(fmt.Errorf("%s: %w", op, err)).Send()
+	}
+}
+
+// Listen calls handle for every received event until Close.
+func (n *Notifier) Listen(handle func(e event.Event)) {
+	const op = "internal.storage.postgres.notifier.Listen"
+
+	for notification := range n.l.Notify {
+		// Connection was restored, events sent meanwhile are lost.
+		if notification == nil {
+			zlog.Logger.Warn().Msg(op + ": listener reconnected")
+			continue
+		}
+
+		var e event.Event
+		if err := json.Unmarshal([]byte(notification.Extra), &e); err != nil {
+			zlog.Logger.Error().Err(fmt.Errorf("%s: %w", op, err)).Send()
+			continue
+		}
+
+		// Comment didn't fit in payload.
+		if e.Type != event.TypeDeleted && e.Comment == nil {
+			c, err := n.p.Parent(e.CommentID)
+			if err != nil {
+				zlog.Logger.Error().Err(fmt.Errorf("%s: %w", op, err)).Send()
+				continue
+			}
+			e.Comment = &c
+		}
+
+		handle(e)
+	}
+}
+
+// Close stops listening, Listen returns after it.
+func (n *Notifier) Close() {
+	_ = n.l.Close()
+}
+
+func notifyPayload(e event.Event) ([]byte, error) {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(payload) > MaxNotifyPayload {
+		e.Comment = nil
+		return json.Marshal(e)
+	}
+
+	return payload, nil
+}
@@ -2,9 +2,11 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"os"
+	"sync/atomic"
 	"time"
 
 	"CommentTree/pkg/errs"
@@ -12,20 +14,30 @@ import (
 	"github.com/lib/pq"
 	"github.com/wb-go/wbf/dbpg"
 	"github.com/wb-go/wbf/retry"
+	"github.com/wb-go/wbf/zlog"
 )
 
 const (
 	// Tables names.
-	CommentsTable = "comments"
+	CommentsTable  = "comments"
+	RevisionsTable = "comment_revisions"
+	UsersTable     = "users"
+	ReactionsTable = "reactions"
+	WebhooksTable  = "webhooks"
+	OutboxTable    = "webhook_outbox"
 
 	// Postgres errors.
 	ViolatesForeignKey = "23503"
+	UniqueViolation    = "23505"
 
 	// Params.
 	ConnMaxLifeTime = 2
 	MaxIdleConns    = 20
 	MaxOpenConns    = 100
 
+	// Text search configuration when no other is set.
+	DefaultSearchLanguage = "english"
+
 	// RetryStrategy.
 	Attempts = 3
 	Delay    = 3
@@ -40,12 +52,33 @@ var (
 	}
 )
 
+type Options struct {
+	// Text search configuration, like english or russian.
+	SearchLanguage string
+	// Connection strings of read replicas, they serve listing
+	// and search of comments.
+	Replicas []string
+}
+
 type Postgres struct {
 	db *dbpg.DB
+	// Text search configuration of search_vector column.
+	lang string
+	// Count of replica reads, it picks replicas by turns.
+	reads atomic.Uint64
 }
 
-func New(conn string) *Postgres {
-	pg, err := dbpg.New(conn, nil, &dbpg.Options{
+func New(conn string, opts *Options) *Postgres {
+	lang := DefaultSearchLanguage
+	if opts != nil && opts.SearchLanguage != "" {
+		lang = opts.SearchLanguage
+	}
+	var replicas []string
+	if opts != nil {
+		replicas = opts.Replicas
+	}
+
+	pg, err := dbpg.New(conn, replicas, &dbpg.Options{
 		MaxOpenConns:    MaxOpenConns,
 		MaxIdleConns:    MaxIdleConns,
 		ConnMaxLifetime: ConnMaxLifeTime * time.Hour,
@@ -61,8 +94,17 @@ func New(conn string) *Postgres {
 		os.Exit(1)
 	}
 
+	// Reads from unavailable replica go to master,
+	// so it doesn't stop start.
+	for i, replica := range pg.Slaves {
+		if err := replica.PingContext(context.Background()); err != nil {
+			zlog.Logger.Warn().Err(err).Int("replica", i).Msg("replica is unavailable")
+		}
+	}
+
 	return &Postgres{
-		db: pg,
+		db:   pg,
+		lang: lang,
 	}
 }
 
@@ -73,12 +115,63 @@ func (p *Postgres) Shutdown() {
 	}
 }
 
+// queryReplica runs read query on the next replica, query goes to master
+// when there are no replicas or replica fails. It's for listing and search,
+// reads by id may come right after changes, so they use queryMaster.
+func (p *Postgres) queryReplica(ctx context.Context, q string, args ...any) (*sql.Rows, error) {
+	const op = "internal.storage.postgres.queryReplica"
+
+	return p.queryWithRetry(func() (*sql.Rows, error) {
+		if len(p.db.Slaves) == 0 {
+			return p.db.Master.QueryContext(ctx, q, args...)
+		}
+
+		i := (p.reads.Add(1) - 1) % uint64(len(p.db.Slaves))
+		rows, err := p.db.Slaves[i].QueryContext(ctx, q, args...)
+		if err == nil {
+			return rows, nil
+		}
+
+		zlog.Logger.Warn().Err(fmt.Errorf("%s: %w", op, err)).
+			Uint64("replica", i).Msg("read goes to master")
+		return p.db.Master.QueryContext(ctx, q, args...)
+	})
+}
+
+func (p *Postgres) queryMaster(ctx context.Context, q string, args ...any) (*sql.Rows, error) {
+	return p.queryWithRetry(func() (*sql.Rows, error) {
+		return p.db.Master.QueryContext(ctx, q, args...)
+	})
+}
+
+// queryWithRetry repeats query by retryOpts like dbpg.QueryWithRetry.
+func (p *Postgres) queryWithRetry(query func() (*sql.Rows, error)) (*sql.Rows, error) {
+	var rows *sql.Rows
+	err := retry.Do(func() error {
+		r, err := query()
+		if err != nil {
+			return err
+		}
+		if err := r.Err(); err != nil {
+			_ = r.Close()
+			return err
+		}
+
+		rows = r
+		return nil
+	}, retryOpts)
+
+	return rows, err
+}
+
 func (p *Postgres) unwrapInternalError(op string, err error) error {
 	var pgErr *pq.Error
 	if errors.As(err, &pgErr) {
 		switch pgErr.Code {
 		case ViolatesForeignKey:
 			return errs.ErrDBViolatesForeignKey
+		case UniqueViolation:
+			return errs.ErrDBAlreadyExists
 		}
 	}
 
@@ -0,0 +1,161 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"CommentTree/internal/entities/comment"
+	"CommentTree/internal/storage/storagetest"
+)
+
+// Database for conformance suite, it's tables are cleared before every test.
+const TestConnEnv = "POSTGRES_TEST_CONN"
+
+func TestPostgres_Conformance(t *testing.T) {
+	conn := os.Getenv(TestConnEnv)
+	if conn == "" {
+		t.Skip(TestConnEnv + " isn't set")
+	}
+
+	q := fmt.Sprintf(
+		"truncate %s, %s, %s, %s, %s, %s restart identity cascade;",
+		CommentsTable, RevisionsTable, ReactionsTable, UsersTable,
+		WebhooksTable, OutboxTable,
+	)
+
+	storagetest.Run(t, func(t *testing.T) storagetest.DB {
+		p := New(conn, nil)
+		if err := p.Migrate(); err != nil {
+			t.Fatalf("Migrate() err = %v", err)
+		}
+		if _, err := p.db.ExecContext(context.Background(), q); err != nil {
+			t.Fatalf("truncate err = %v", err)
+		}
+
+		return p
+	})
+}
+
+func TestPostgres_generateAgrigatedOpts(t *testing.T) {
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name     string // description of this test case
+		parentID int64
+		opts     *comment.GetterOpts
+		wantArgs []any
+		// Parts of query.
+		want    []string
+		notWant []string
+	}{
+		{
+			name:     "roots",
+			opts:     nil,
+			wantArgs: []any{comment.StatusApproved},
+			want: []string{
+				"parent_id is NULL", "status = $1",
+				"order by created_at, id", "limit 11 offset 0",
+			},
+		},
+		{
+			name:     "second page of replies",
+			parentID: 5,
+			opts:     &comment.GetterOpts{Page: 2},
+			wantArgs: []any{int64(5), comment.StatusApproved},
+			want:     []string{"parent_id = $1", "status = $2", "limit 11 offset 10"},
+			notWant:  []string{"parent_id is NULL"},
+		},
+		{
+			name:     "any status in thread",
+			opts:     &comment.GetterOpts{AnyStatus: true, ThreadKey: "article-1"},
+			wantArgs: []any{"article-1"},
+			want:     []string{"thread_key = $1"},
+			notWant:  []string{"status = $"},
+		},
+		{
+			name:     "global substr",
+			opts:     &comment.GetterOpts{SearchGlobal: true, Substr: "hi"},
+			wantArgs: []any{comment.StatusApproved, "hi"},
+			want:     []string{"POSITION($2 IN message) > 0", "deleted_at is NULL"},
+			notWant:  []string{"parent_id is NULL", "parent_id = $"},
+		},
+		{
+			name: "fulltext",
+			opts: &comment.GetterOpts{
+				Substr: "cats", SearchMode: comment.SearchFulltext,
+			},
+			wantArgs: []any{comment.StatusApproved, DefaultSearchLanguage, "cats"},
+			want: []string{
+				"search_vector @@ websearch_to_tsquery($2::regconfig, $3)",
+				"order by rank desc, id desc",
+			},
+		},
+		{
+			name: "fuzzy escapes wildcards",
+			opts: &comment.GetterOpts{
+				Substr: "50%", SearchMode: comment.SearchFuzzy,
+			},
+			wantArgs: []any{comment.StatusApproved, `%50\%%`, "50%"},
+			want: []string{
+				"message ILIKE $2 or $3 <% message",
+				"order by similarity desc, id desc",
+			},
+		},
+		{
+			name: "fulltext ignores cursor",
+			opts: &comment.GetterOpts{
+				Substr: "cats", SearchMode: comment.SearchFulltext,
+				After: &comment.Cursor{ID: 7},
+			},
+			wantArgs: []any{comment.StatusApproved, DefaultSearchLanguage, "cats"},
+			want:     []string{"limit 11 offset 0"},
+		},
+		{
+			name: "cursor of newest",
+			opts: &comment.GetterOpts{
+				Sort:  comment.SortNewest,
+				After: &comment.Cursor{CreatedAt: createdAt, ID: 7},
+			},
+			wantArgs: []any{comment.StatusApproved, createdAt, int64(7)},
+			want: []string{
+				"where (created_at, id) < ($2, $3)",
+				"order by created_at desc, id desc limit 11",
+			},
+			notWant: []string{"offset"},
+		},
+		{
+			name: "cursor of top",
+			opts: &comment.GetterOpts{
+				Sort:  comment.SortTop,
+				After: &comment.Cursor{Sort: comment.SortTop, Rank: -2, ID: 7},
+			},
+			wantArgs: []any{comment.StatusApproved, int64(-2), int64(7)},
+			want:     []string{"where (score, id) < ($2, $3)", "order by score desc, id desc"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Postgres{lang: DefaultSearchLanguage}
+
+			args, q := p.generateAgrigatedOpts(tt.parentID, tt.opts)
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("generateAgrigatedOpts() args = %v, want %v", args, tt.wantArgs)
+			}
+			for _, part := range tt.want {
+				if !strings.Contains(q, part) {
+					t.Errorf("generateAgrigatedOpts() query doesn't have %q:\n%s", part, q)
+				}
+			}
+			for _, part := range tt.notWant {
+				if strings.Contains(q, part) {
+					t.Errorf("generateAgrigatedOpts() query has %q:\n%s", part, q)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"CommentTree/internal/entities/comment"
+	"CommentTree/pkg/errs"
+)
+
+// AddReaction sets reaction of user on comment, new vote replaces
+// opposite one. Setting existing reaction again isn't error.
+// Readers don't see not approved comments, so they can't react on them.
+func (p *Postgres) AddReaction(commentID, userID int64, kind string) error {
+	const op = "internal.storage.postgres.reactions.Add"
+
+	q := fmt.Sprintf(`
+		with vote as (
+			delete from %[1]s where comment_id = $1 and user_id = $2 and kind = $4
+		)
+		insert into %[1]s (comment_id, user_id, kind)
+		select id, $2, $3 from %[2]s
+		where id = $1 and deleted_at is null and status = $5
+		on conflict (comment_id, user_id, kind)
+		do update set created_at = %[1]s.created_at;`,
+		ReactionsTable, CommentsTable,
+	)
+
+	res, err := p.db.ExecContext(
+		context.Background(), q,
+		commentID, userID, kind, comment.OppositeVote(kind), comment.StatusApproved,
+	)
+	if err != nil {
+		return p.unwrapInternalError(op, err)
+	}
+
+	// Nothing inserted only when comment doesn't exist, deleted or not approved.
+	if affected, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	} else if affected == 0 {
+		return errs.ErrDBNotAffected
+	}
+
+	return nil
+}
+
+func (p *Postgres) RemoveReaction(commentID, userID int64, kind string) error {
+	const op = "internal.storage.postgres.reactions.Remove"
+
+	q := fmt.Sprintf(
+		"delete from %s where comment_id = $1 and user_id = $2 and kind = $3",
+		ReactionsTable,
+	)
+
+	res, err := p.db.ExecContext(
+		context.Background(), q, commentID, userID, kind,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	} else if affected == 0 {
+		return errs.ErrDBNotAffected
+	}
+
+	return nil
+}
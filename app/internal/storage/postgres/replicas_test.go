@@ -0,0 +1,159 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/wb-go/wbf/dbpg"
+)
+
+// Driver of fake databases, query returns one row with name of
+// database, databases with "down" in name fail.
+const fakeDriver = "postgres_fake"
+
+func init() {
+	sql.Register(fakeDriver, fakeDB{})
+}
+
+type fakeDB struct{}
+
+func (fakeDB) Open(name string) (driver.Conn, error) {
+	return &fakeConn{name: name}, nil
+}
+
+type fakeConn struct {
+	name string
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *fakeConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	if strings.Contains(c.name, "down") {
+		return nil, driver.ErrBadConn
+	}
+
+	return &fakeRows{name: c.name}, nil
+}
+
+type fakeRows struct {
+	name string
+	done bool
+}
+
+func (r *fakeRows) Columns() []string { return []string{"name"} }
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.name
+
+	return nil
+}
+
+func fakePostgres(t *testing.T, replicas ...string) *Postgres {
+	t.Helper()
+
+	open := func(name string) *sql.DB {
+		db, err := sql.Open(fakeDriver, name)
+		if err != nil {
+			t.Fatalf("sql.Open() err = %v", err)
+		}
+		t.Cleanup(func() {
+			_ = db.Close()
+		})
+
+		return db
+	}
+
+	db := &dbpg.DB{Master: open("master")}
+	for _, r := range replicas {
+		db.Slaves = append(db.Slaves, open(r))
+	}
+
+	return &Postgres{db: db}
+}
+
+func TestPostgres_queryReplica(t *testing.T) {
+	tests := []struct {
+		name     string // description of this test case
+		replicas []string
+		want     []string
+	}{
+		{
+			name: "without replicas",
+			want: []string{"master", "master"},
+		},
+		{
+			name:     "by turns",
+			replicas: []string{"r1", "r2"},
+			want:     []string{"r1", "r2", "r1"},
+		},
+		{
+			name:     "fallback to master",
+			replicas: []string{"r1", "r2-down"},
+			want:     []string{"r1", "master", "r1", "master"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := fakePostgres(t, tt.replicas...)
+
+			for i, want := range tt.want {
+				rows, err := p.queryReplica(context.Background(), "select name")
+				if err != nil {
+					t.Fatalf("queryReplica() err = %v", err)
+				}
+
+				var got string
+				if !rows.Next() {
+					t.Fatalf("queryReplica() returned no rows")
+				}
+				if err := rows.Scan(&got); err != nil {
+					t.Fatalf("Scan() err = %v", err)
+				}
+				_ = rows.Close()
+
+				if got != want {
+					t.Errorf("queryReplica() #%d from %v, want %v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPostgres_queryMaster(t *testing.T) {
+	p := fakePostgres(t, "r1")
+
+	rows, err := p.queryMaster(context.Background(), "select name")
+	if err != nil {
+		t.Fatalf("queryMaster() err = %v", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var got string
+	if rows.Next() {
+		_ = rows.Scan(&got)
+	}
+	if got != "master" {
+		t.Errorf("queryMaster() from %v, want master", got)
+	}
+}
@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"CommentTree/internal/entities/user"
+	"CommentTree/pkg/errs"
+)
+
+func (p *Postgres) CreateUser(u user.User, tokenHash string) (int64, error) {
+	const op = "internal.storage.postgres.users.Create"
+
+	q := fmt.Sprintf(
+		"insert into %s (name, token_hash, role) values ($1, $2, $3) returning id;",
+		UsersTable,
+	)
+
+	var id int64
+	err := p.db.Master.QueryRowContext(
+		context.Background(), q, u.Name, tokenHash, u.Role,
+	).Scan(&id)
+	if err != nil {
+		return 0, p.unwrapInternalError(op, err)
+	}
+
+	return id, nil
+}
+
+func (p *Postgres) UserByToken(tokenHash string) (user.User, error) {
+	const op = "internal.storage.postgres.users.byToken"
+
+	q := fmt.Sprintf(
+		"select id, name, role from %s where token_hash = $1", UsersTable,
+	)
+
+	var u user.User
+	err := p.db.Master.QueryRowContext(context.Background(), q, tokenHash).
+		Scan(&u.ID, &u.Name, &u.Role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return user.User{}, errs.ErrDBNotFound
+	} else if err != nil {
+		return user.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return u, nil
+}
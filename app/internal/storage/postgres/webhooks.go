@@ -0,0 +1,202 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"CommentTree/internal/entities/comment"
+	"CommentTree/internal/entities/event"
+	"CommentTree/internal/entities/webhook"
+	"CommentTree/pkg/errs"
+
+	"github.com/lib/pq"
+)
+
+// enqueueWebhooks writes event typ about comment id to outbox for every
+// webhook which waits for it, it should go in transaction of the change.
+// Events about not approved comments aren't written.
+func enqueueWebhooks(ctx context.Context, tx *sql.Tx, typ string, id int64) error {
+	// Object has fields of webhook.Payload.
+	q := fmt.Sprintf(`
+		insert into %[1]s (webhook_id, event, payload)
+		select w.id, $1::text, jsonb_strip_nulls(jsonb_build_object(
+			'event', $1::text,
+			'comment_id', c.id,
+			'parent_id', coalesce(c.parent_id, 0),
+			'thread_key', c.thread_key,
+			'author_id', c.author_id,
+			'message', case when $1::text = '%[4]s' then c.message end,
+			'occurred_at', now()
+		))
+		from %[2]s w join %[3]s c on c.id = $2
+		where $1::text = any(w.events) and c.status = '%[5]s';`,
+		OutboxTable, WebhooksTable, CommentsTable,
+		event.TypeCreated, comment.StatusApproved,
+	)
+
+	_, err := tx.ExecContext(ctx, q, typ, id)
+	return err
+}
+
+func (p *Postgres) CreateWebhook(w webhook.Webhook) (webhook.Webhook, error) {
+	const op = "internal.storage.postgres.webhooks.Create"
+
+	q := fmt.Sprintf(
+		`insert into %s (url, secret, events) values ($1, $2, $3)
+		returning id, created_at;`,
+		WebhooksTable,
+	)
+
+	err := p.db.Master.QueryRowContext(
+		context.Background(), q, w.URL, w.Secret, pq.Array(w.Events),
+	).Scan(&w.ID, &w.CreatedAt)
+	if err != nil {
+		return webhook.Webhook{}, p.unwrapInternalError(op, err)
+	}
+
+	return w, nil
+}
+
+// Webhooks returns all webhooks without secrets.
+func (p *Postgres) Webhooks() ([]webhook.Webhook, error) {
+	const op = "internal.storage.postgres.webhooks.list"
+
+	q := fmt.Sprintf(
+		"select id, url, events, created_at from %s order by id;",
+		WebhooksTable,
+	)
+
+	// Webhook is listed right after it's created.
+	rows, err := p.queryMaster(context.Background(), q)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	hooks := []webhook.Webhook{}
+	for rows.Next() {
+		var w webhook.Webhook
+
+		err := rows.Scan(&w.ID, &w.URL, pq.Array(&w.Events), &w.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		hooks = append(hooks, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return hooks, nil
+}
+
+// DeleteWebhook removes webhook with it's not sent deliveries.
+func (p *Postgres) DeleteWebhook(id int64) error {
+	const op = "internal.storage.postgres.webhooks.Delete"
+
+	q := fmt.Sprintf("delete from %s where id = $1", WebhooksTable)
+
+	res, err := p.db.ExecContext(context.Background(), q, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	} else if affected == 0 {
+		return errs.ErrDBNotAffected
+	}
+
+	return nil
+}
+
+// ClaimDeliveries takes up to limit due deliveries and hides them from
+// other dispatchers for lease. Deliveries which were tried maxAttempts
+// times stay in outbox with their last error.
+func (p *Postgres) ClaimDeliveries(limit int, lease time.Duration, maxAttempts int) ([]webhook.Delivery, error) {
+	const op = "internal.storage.postgres.webhooks.ClaimDeliveries"
+
+	q := fmt.Sprintf(`
+		with due as (
+			select id from %[1]s
+			where delivered_at is null and next_attempt_at <= now()
+				and attempts < $3
+			order by id limit $1
+			for update skip locked
+		)
+		update %[1]s o set attempts = o.attempts + 1,
+			next_attempt_at = now() + $2 * interval '1 millisecond'
+		from due, %[2]s w
+		where o.id = due.id and w.id = o.webhook_id
+		returning o.id, o.webhook_id, w.url, w.secret, o.event, o.payload, o.attempts;`,
+		OutboxTable, WebhooksTable,
+	)
+
+	rows, err := p.db.Master.QueryContext(
+		context.Background(), q, limit, lease.Milliseconds(), maxAttempts,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	deliveries := []webhook.Delivery{}
+	for rows.Next() {
+		var d webhook.Delivery
+
+		err := rows.Scan(
+			&d.ID, &d.WebhookID, &d.URL, &d.Secret,
+			&d.Event, &d.Payload, &d.Attempts,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deliveries, nil
+}
+
+func (p *Postgres) CompleteDelivery(id int64) error {
+	const op = "internal.storage.postgres.webhooks.CompleteDelivery"
+
+	q := fmt.Sprintf(
+		`update %s set delivered_at = now(), last_error = null where id = $1`,
+		OutboxTable,
+	)
+
+	if _, err := p.db.ExecContext(context.Background(), q, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// FailDelivery saves error of delivery, it's tried again at retryAt.
+func (p *Postgres) FailDelivery(id int64, reason string, retryAt time.Time) error {
+	const op = "internal.storage.postgres.webhooks.FailDelivery"
+
+	q := fmt.Sprintf(
+		`update %s set last_error = $2, next_attempt_at = $3 where id = $1`,
+		OutboxTable,
+	)
+
+	if _, err := p.db.ExecContext(context.Background(), q, id, reason, retryAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
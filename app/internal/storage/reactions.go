@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+
+	"CommentTree/pkg/errs"
+)
+
+func (s *Storage) AddReaction(commentID, userID int64, kind string) error {
+	const op = "internal.storage.AddReaction"
+
+	err := s.db.AddReaction(commentID, userID, kind)
+	if errors.Is(err, errs.ErrDBNotAffected) {
+		return ErrNotFound
+	} else if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) RemoveReaction(commentID, userID int64, kind string) error {
+	const op = "internal.storage.RemoveReaction"
+
+	err := s.db.RemoveReaction(commentID, userID, kind)
+	if errors.Is(err, errs.ErrDBNotAffected) {
+		return ErrNotAffected
+	} else if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,754 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"CommentTree/internal/entities/comment"
+	"CommentTree/internal/entities/event"
+	"CommentTree/internal/storage/textsearch"
+	"CommentTree/pkg/errs"
+
+	"github.com/wb-go/wbf/zlog"
+)
+
+// Columns of comments table in order of scanComment.
+var commentFields = []string{
+	"id", "message", "parent_id",
+	"deleted_at", "edited_at", "author_id", "created_at", "updated_at",
+	"thread_key", "status", "deleted_by",
+}
+
+// commentColumnsOf returns commentFields of table with alias.
+func commentColumnsOf(alias string) string {
+	cols := make([]string, 0, len(commentFields))
+	for _, f := range commentFields {
+		cols = append(cols, alias+"."+f)
+	}
+
+	return strings.Join(cols, ", ")
+}
+
+// Escapes wildcards of LIKE pattern, so they are searched as is.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// Counts of direct replies, all descendants and reactions,
+// used with comments table aliased as c. Only approved replies
+// are counted, so count is the same as readers see.
+var countColumns = fmt.Sprintf(`
+	(
+		select count(*) from %[1]s r
+		where r.parent_id = c.id and r.status = '%[5]s'
+	) as reply_count,
+	(
+		with recursive d as (
+			select id from %[1]s where parent_id = c.id and status = '%[5]s'
+			union all
+			select r.id from %[1]s r join d on r.parent_id = d.id
+			where r.status = '%[5]s'
+		)
+		select count(*) from d
+	) as descendant_count,
+	(
+		select coalesce(sum(case kind when '%[3]s' then 1 when '%[4]s' then -1 else 0 end), 0)
+		from %[2]s where comment_id = c.id
+	) as score,
+	(
+		select case when count(*) > 0 then json_group_object(kind, n) end from (
+			select kind, count(*) as n from %[2]s
+			where comment_id = c.id group by kind
+		) k
+	) as reactions`,
+	CommentsTable, ReactionsTable, comment.ReactionUp, comment.ReactionDown,
+	comment.StatusApproved,
+)
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanComment scans commentFields, countColumns and then extra destinations.
+func scanComment(row scanner, extra ...any) (comment.Comment, error) {
+	var c comment.Comment
+	var parentID sql.NullInt64
+	var deletedAt, editedAt, createdAt, updatedAt nullTime
+	var authorID sql.NullInt64
+	var deletedBy sql.NullInt64
+	var reactions []byte
+
+	dest := append(
+		[]any{
+			&c.ID, &c.Message, &parentID,
+			&deletedAt, &editedAt, &authorID, &createdAt, &updatedAt,
+			&c.ThreadKey, &c.Status, &deletedBy, &c.ReplyCount, &c.DescendantCount, &c.Score, &reactions,
+		},
+		extra...,
+	)
+	if err := row.Scan(dest...); err != nil {
+		return comment.Comment{}, err
+	}
+
+	c.CreatedAt = createdAt.Time
+	c.UpdatedAt = updatedAt.Time
+	if parentID.Valid {
+		c.ParentID = parentID.Int64
+	}
+	if editedAt.Valid {
+		c.EditedAt = &editedAt.Time
+	}
+	if authorID.Valid {
+		c.AuthorID = authorID.Int64
+	}
+	if deletedBy.Valid {
+		c.DeletedBy = deletedBy.Int64
+	}
+	// NULL when comment has no reactions.
+	if len(reactions) > 0 {
+		if err := json.Unmarshal(reactions, &c.Reactions); err != nil {
+			return comment.Comment{}, err
+		}
+	}
+	// Tombstone keeps place in the tree, but not it's text.
+	if deletedAt.Valid {
+		c.Deleted = true
+		c.Message = comment.DeletedMessage
+	}
+
+	return c, nil
+}
+
+func (s *SQLite) issetComment(id int64) bool {
+	const op = "internal.storage.sqlite.comments.issetComment"
+
+	// Invisible root comment for others with id 0.
+	if id == 0 {
+		return true
+	}
+
+	// Can't reply to deleted or not approved comment.
+	q := fmt.Sprintf(
+		"select id from %s where id = ?1 and deleted_at is null and status = ?2",
+		CommentsTable,
+	)
+
+	var tmp int64
+	err := s.db.QueryRowContext(
+		context.Background(), q, id, comment.StatusApproved,
+	).Scan(&tmp)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false
+	} else if err != nil {
+		zlog.Logger.Error().Err(fmt.Errorf("%s: %w", op, err)).Send()
+		return false
+	}
+
+	return true
+}
+
+func (s *SQLite) CreateComment(c comment.Comment) (int64, error) {
+	const op = "internal.storage.sqlite.comments.Create"
+
+	if !s.issetComment(c.ParentID) {
+		return 0, errs.ErrDBViolatesForeignKey
+	}
+
+	// Reply always belongs to thread of it's parent.
+	q := fmt.Sprintf(`
+		insert into %[1]s (
+			message, parent_id, author_id, thread_key, status,
+			created_at, updated_at, published
+		)
+		values (
+			?1, ?2, ?3, coalesce((select thread_key from %[1]s where id = ?2), ?4),
+			?5, ?6, ?6, ?5 = '%[2]s'
+		);`,
+		CommentsTable, comment.StatusApproved,
+	)
+
+	// if we don't have parent id, we will insert NULL to db
+	parentIDArg := sql.NullInt64{
+		Int64: c.ParentID, Valid: c.ParentID > 0,
+	}
+	status := c.Status
+	if status == "" {
+		status = comment.StatusApproved
+	}
+	// Same for anonymous author.
+	authorIDArg := sql.NullInt64{
+		Int64: c.AuthorID, Valid: c.AuthorID > 0,
+	}
+
+	ctx := context.Background()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	res, err := tx.ExecContext(
+		ctx, q,
+		c.Message, parentIDArg, authorIDArg, c.ThreadKey, status, now(),
+	)
+	if err != nil {
+		return 0, s.unwrapInternalError(op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Pending comment is sent to webhooks after approve.
+	if err := enqueueWebhooks(ctx, tx, event.TypeCreated, id); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (s *SQLite) generateAgrigatedOpts(parentID int64, opts *comment.GetterOpts) ([]any, string) {
+	if opts == nil {
+		opts = &comment.GetterOpts{}
+	}
+
+	// Initialization values.
+	args := []any{}
+	conds := []string{}
+	// Rank and snippet have sense only for fulltext search,
+	// similarity only for fuzzy.
+	searchCols := "0.0 as rank, '' as snippet, 0.0 as similarity"
+
+	argIDX := 1
+	if parentID > 0 {
+		conds = append(conds, fmt.Sprintf("parent_id = ?%d", argIDX))
+		args = append(args, parentID)
+		argIDX++
+	} else if !opts.SearchGlobal {
+		// Search in all comments only with SearchGlobal,
+		// otherwise invisible root has only roots as replies.
+		conds = append(conds, "parent_id is NULL")
+	}
+
+	if !opts.AnyStatus {
+		status := opts.Status
+		if status == "" {
+			status = comment.StatusApproved
+		}
+		conds = append(conds, fmt.Sprintf("status = ?%d", argIDX))
+		args = append(args, status)
+		argIDX++
+	}
+
+	if opts.ThreadKey != "" {
+		conds = append(conds, fmt.Sprintf("thread_key = ?%d", argIDX))
+		args = append(args, opts.ThreadKey)
+		argIDX++
+	}
+
+	// Text of deleted comments is hidden, so we don't search in it.
+	if opts.Substr != "" && opts.SearchMode == comment.SearchFulltext {
+		conds = append(conds, fmt.Sprintf(
+			"fulltext_rank(?%d, message) > 0 and deleted_at is NULL", argIDX,
+		))
+		searchCols = fmt.Sprintf(
+			"fulltext_rank(?%[1]d, c.message) as rank, "+
+				"fulltext_snippet(?%[1]d, c.message) as snippet, "+
+				"0.0 as similarity",
+			argIDX,
+		)
+		args = append(args, opts.Substr)
+		argIDX++
+	} else if opts.Substr != "" && opts.SearchMode == comment.SearchFuzzy {
+		// Both sides are lowered, so LIKE ignores case of any
+		// alphabet like ILIKE of postgres.
+		conds = append(conds, fmt.Sprintf(
+			`(unicode_lower(message) like unicode_lower(?%[1]d) escape '\'
+				or word_similarity(?%[2]d, message) >= %[3]g)
+			and deleted_at is NULL`,
+			argIDX, argIDX+1, textsearch.SimilarityThreshold,
+		))
+		searchCols = fmt.Sprintf(
+			"0.0 as rank, '' as snippet, "+
+				"word_similarity(?%d, c.message) as similarity",
+			argIDX+1,
+		)
+		args = append(args, "%"+likeEscaper.Replace(opts.Substr)+"%", opts.Substr)
+		argIDX += 2
+	} else if opts.Substr != "" {
+		conds = append(conds, fmt.Sprintf(
+			"instr(message, ?%d) > 0 and deleted_at is NULL", argIDX,
+		))
+		args = append(args, opts.Substr)
+		argIDX++
+	}
+
+	argsStr := fmt.Sprintf(
+		"select %s, %s, %s from %s c",
+		commentColumnsOf("c"), countColumns, searchCols, CommentsTable,
+	)
+	if len(conds) > 0 {
+		argsStr += " where " + strings.Join(conds, " and ")
+	}
+
+	// Sorting by computed columns is done in outer query,
+	// so cursor conditions can use them like usual columns.
+	argsStr = fmt.Sprintf("select * from (%s) s", argsStr)
+
+	var order, cursorCond string
+	switch {
+	case opts.SearchMode == comment.SearchFulltext && opts.Substr != "":
+		// Most relevant first, fulltext search is paged only by page.
+		order = "rank desc, id desc"
+	case opts.SearchMode == comment.SearchFuzzy && opts.Substr != "":
+		order = "similarity desc, id desc"
+	case opts.Sort == comment.SortNewest:
+		order = "created_at desc, id desc"
+		cursorCond = "(created_at, id) < (?%d, ?%d)"
+	case opts.Sort == comment.SortMostReplied:
+		order = "reply_count desc, id desc"
+		cursorCond = "(reply_count, id) < (?%d, ?%d)"
+	case opts.Sort == comment.SortTop:
+		order = "score desc, id desc"
+		cursorCond = "(score, id) < (?%d, ?%d)"
+	default:
+		order = "created_at, id"
+		cursorCond = "(created_at, id) > (?%d, ?%d)"
+	}
+
+	if opts.After != nil && cursorCond != "" {
+		if opts.Sort == comment.SortMostReplied || opts.Sort == comment.SortTop {
+			args = append(args, opts.After.Rank, opts.After.ID)
+		} else {
+			args = append(args, formatTime(opts.After.CreatedAt), opts.After.ID)
+		}
+
+		argsStr += " where " + fmt.Sprintf(cursorCond, argIDX, argIDX+1)
+	}
+
+	argsStr += " order by " + order
+
+	// One more row shows that we have next page.
+	limit := comment.PageElements + 1
+	if opts.After != nil && cursorCond != "" {
+		argsStr += fmt.Sprintf(" limit %d", limit)
+		return args, argsStr
+	}
+
+	page := opts.Page
+	if page > 0 {
+		// First page has zero offset.
+		page--
+	}
+
+	offset := comment.PageElements * page
+	argsStr += fmt.Sprintf(" limit %d offset %d", limit, offset)
+
+	return args, argsStr
+}
+
+func (s *SQLite) Parent(id int64) (comment.Comment, error) {
+	const op = "internal.storage.sqlite.comments.parent"
+
+	qParent := fmt.Sprintf(
+		`select %s, %s from %s c where id = ?1`,
+		commentColumnsOf("c"), countColumns, CommentsTable,
+	)
+
+	row := s.db.QueryRowContext(context.Background(), qParent, id)
+	parent, err := scanComment(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return comment.Comment{}, errs.ErrDBNotFound
+	} else if err != nil {
+		return comment.Comment{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return parent, nil
+}
+
+func (s *SQLite) Single(id int64, anyStatus bool) (comment.SingleView, error) {
+	// Path ends with the comment.
+	path, err := s.Path(id, anyStatus)
+	if err != nil {
+		return comment.SingleView{}, err
+	}
+
+	return comment.SingleView{
+		Comment: path[len(path)-1],
+		RootID:  path[0].ID,
+		Depth:   int64(len(path) - 1),
+	}, nil
+}
+
+// Path returns chain of comments from the root, without anyStatus
+// it isn't found if some comment of chain isn't approved.
+func (s *SQLite) Path(id int64, anyStatus bool) ([]comment.Comment, error) {
+	const op = "internal.storage.sqlite.comments.path"
+
+	// Walk up by parent_id, the last found ancestor is the root.
+	q := fmt.Sprintf(`
+		with recursive ancestors as (
+			select id, parent_id, 0 as depth from %[1]s where id = ?1
+			union all
+			select c.id, c.parent_id, a.depth + 1
+			from %[1]s c join ancestors a on c.id = a.parent_id
+		)
+		select %[2]s, %[3]s
+		from ancestors a join %[1]s c on c.id = a.id
+		order by a.depth desc;`,
+		CommentsTable, commentColumnsOf("c"), countColumns,
+	)
+
+	rows, err := s.db.QueryContext(context.Background(), q, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	path := []comment.Comment{}
+	for rows.Next() {
+		tmp, err := scanComment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		// Readers see neither not approved comment nor it's replies.
+		if !anyStatus && !tmp.Public() {
+			return nil, errs.ErrDBNotFound
+		}
+
+		path = append(path, tmp)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(path) == 0 {
+		return nil, errs.ErrDBNotFound
+	}
+
+	return path, nil
+}
+
+func (s *SQLite) Childs(parentID int64, opts *comment.GetterOpts) ([]comment.Comment, error) {
+	const op = "internal.storage.sqlite.comments.childs"
+
+	args, q := s.generateAgrigatedOpts(parentID, opts)
+	rows, err := s.db.QueryContext(context.Background(), q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	coms := []comment.Comment{}
+	for rows.Next() {
+		var rank, similarity float64
+		var snippet string
+		tmp, err := scanComment(rows, &rank, &snippet, &similarity)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		tmp.Rank = rank
+		tmp.Snippet = snippet
+		tmp.Similarity = similarity
+
+		// Every comment except the last one have next.
+		tmp.HaveNext = true
+		coms = append(coms, tmp)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Extra row only shows that we have next page.
+	if len(coms) > comment.PageElements {
+		coms = coms[:comment.PageElements]
+	} else if len(coms) > 0 {
+		coms[len(coms)-1].HaveNext = false
+	}
+
+	return coms, nil
+}
+
+func (s *SQLite) Tree(id int64, opts *comment.TreeOpts) (*comment.TreeNode, error) {
+	const op = "internal.storage.sqlite.comments.tree"
+
+	// Readers don't see tree under not approved comment or ancestor.
+	if !opts.AnyStatus {
+		if _, err := s.Path(id, false); err != nil {
+			return nil, err
+		}
+	}
+
+	/*
+		Every node loads limit+1 replies, so we know that node have more
+		replies than limit, but only first limit replies go deeper.
+		Sqlite has no lateral joins, so replies are numbered beforehand.
+	*/
+	q := fmt.Sprintf(`
+		with recursive numbered as (
+			select id, parent_id,
+				row_number() over (
+					partition by parent_id order by created_at, id
+				) as rn
+			from %[1]s where status = ?5
+		), tree as (
+			select id, parent_id, 0 as depth, 1 as rn
+			from %[1]s where id = ?1
+			union all
+			select n.id, n.parent_id, t.depth + 1, n.rn
+			from tree t join numbered n on n.parent_id = t.id
+			where t.depth < ?2 and t.rn <= ?3 and n.rn <= ?4
+		)
+		select %[2]s, %[3]s, t.rn
+		from tree t join %[1]s c on c.id = t.id
+		order by t.depth, t.parent_id, t.rn;`,
+		CommentsTable, commentColumnsOf("c"), countColumns,
+	)
+
+	rows, err := s.db.QueryContext(
+		context.Background(),
+		q, id, opts.MaxDepth, opts.Limit, opts.Limit+1, comment.StatusApproved,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var root *comment.TreeNode
+	nodes := make(map[int64]*comment.TreeNode)
+	for rows.Next() {
+		var rn int
+		c, err := scanComment(rows, &rn)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		node := comment.TreeNode{Comment: c}
+
+		if root == nil {
+			node.Replies = []*comment.TreeNode{}
+			root = &node
+			nodes[node.ID] = root
+			continue
+		}
+
+		parent, ok := nodes[node.ParentID]
+		if !ok {
+			continue
+		}
+		// Extra reply only marks that parent have more replies.
+		if rn > opts.Limit {
+			parent.Replies[len(parent.Replies)-1].HaveNext = true
+			continue
+		}
+
+		node.Replies = []*comment.TreeNode{}
+		nodes[node.ID] = &node
+		parent.Replies = append(parent.Replies, &node)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if root == nil {
+		return nil, errs.ErrDBNotFound
+	}
+
+	return root, nil
+}
+
+// EditComment saves current message of comment to revisions
+// and replaces it with new one, status isn't changed if it's empty.
+func (s *SQLite) EditComment(id int64, message, status string) error {
+	const op = "internal.storage.sqlite.comments.Edit"
+
+	qRevision := fmt.Sprintf(`
+		insert into %s (comment_id, message, created_at)
+		select id, message, ?2 from %s where id = ?1 and deleted_at is null;`,
+		RevisionsTable, CommentsTable,
+	)
+	q := fmt.Sprintf(
+		`update %s set message = ?2, edited_at = ?3, updated_at = ?3,
+			status = coalesce(nullif(?4, ''), status)
+		where id = ?1`,
+		CommentsTable,
+	)
+
+	ctx := context.Background()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	t := now()
+	res, err := tx.ExecContext(ctx, qRevision, id, t)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Revision isn't written only for deleted or missing comment.
+	if affected, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	} else if affected == 0 {
+		return errs.ErrDBNotAffected
+	}
+
+	if _, err := tx.ExecContext(ctx, q, id, message, t, status); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *SQLite) Revisions(id int64, anyStatus bool) ([]comment.Revision, error) {
+	const op = "internal.storage.sqlite.comments.revisions"
+
+	// Path isn't found if comment or it's ancestor is hidden from readers.
+	path, err := s.Path(id, anyStatus)
+	if err != nil {
+		return nil, err
+	}
+	c := path[len(path)-1]
+
+	revs := []comment.Revision{}
+	// History of deleted comment is hidden as it's message.
+	if c.Deleted {
+		return revs, nil
+	}
+
+	q := fmt.Sprintf(`
+		select id, comment_id, message, created_at from %s
+		where comment_id = ?1 order by created_at, id;`,
+		RevisionsTable,
+	)
+
+	rows, err := s.db.QueryContext(context.Background(), q, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		var rev comment.Revision
+		var createdAt nullTime
+
+		err := rows.Scan(&rev.ID, &rev.CommentID, &rev.Message, &createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		rev.CreatedAt = createdAt.Time
+
+		revs = append(revs, rev)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return revs, nil
+}
+
+// DeleteComment marks comment as deleted by user and keeps it's replies,
+// with cascade it removes comment with all replies from table.
+func (s *SQLite) DeleteComment(id, userID int64, cascade bool) error {
+	const op = "internal.storage.sqlite.comments.Delete"
+
+	q := fmt.Sprintf(
+		`update %s set deleted_at = ?2, updated_at = ?2, deleted_by = ?3
+		where id = ?1 and deleted_at is null`,
+		CommentsTable,
+	)
+	userIDArg := sql.NullInt64{
+		Int64: userID, Valid: userID > 0,
+	}
+	args := []any{id, now(), userIDArg}
+	if cascade {
+		q = fmt.Sprintf("delete from %s where id = ?1", CommentsTable)
+		args = args[:1]
+	}
+
+	ctx := context.Background()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	// Event is written before delete, when comment is still in table,
+	// it's rolled back if nothing was deleted.
+	if err := enqueueWebhooks(ctx, tx, event.TypeDeleted, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := tx.ExecContext(ctx, q, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	} else if affected == 0 {
+		return errs.ErrDBNotAffected
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *SQLite) RestoreComment(id int64) error {
+	const op = "internal.storage.sqlite.comments.Restore"
+
+	q := fmt.Sprintf(
+		`update %s set deleted_at = NULL, deleted_by = NULL, updated_at = ?2
+		where id = ?1 and deleted_at is not null`,
+		CommentsTable,
+	)
+
+	res, err := s.db.ExecContext(context.Background(), q, id, now())
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	} else if affected == 0 {
+		return errs.ErrDBNotAffected
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+create table users (
+    id integer primary key,
+    name text not null unique,
+    token_hash text not null unique,
+    role text not null default 'user',
+    created_at timestamp not null
+);
+
+create table comments (
+    id integer primary key,
+    message text not null,
+    parent_id integer null,
+    deleted_at timestamp null,
+    edited_at timestamp null,
+    author_id integer null,
+    -- Identifier of external resource, like article or product.
+    thread_key text not null default '',
+    -- pending, approved or rejected.
+    status text not null default 'approved',
+    created_at timestamp not null,
+    updated_at timestamp not null,
+
+    foreign key (parent_id) references comments(id) on delete cascade,
+    foreign key (author_id) references users(id) on delete set null
+);
+
+create index comments_parent_created_idx on comments (parent_id, created_at, id);
+create index comments_thread_created_idx on comments (thread_key, parent_id, created_at, id);
+create index comments_status_created_idx on comments (status, created_at, id);
+
+create table comment_revisions (
+    id integer primary key,
+    comment_id integer not null,
+    message text not null,
+    created_at timestamp not null,
+
+    foreign key (comment_id) references comments(id) on delete cascade
+);
+
+create index comment_revisions_comment_id_idx on comment_revisions (comment_id);
+
+create table reactions (
+    comment_id integer not null,
+    user_id integer not null,
+    -- up, down or emoji.
+    kind text not null,
+    created_at timestamp not null,
+
+    primary key (comment_id, user_id, kind),
+    foreign key (comment_id) references comments(id) on delete cascade,
+    foreign key (user_id) references users(id) on delete cascade
+);
+
+create table webhooks (
+    id integer primary key,
+    url text not null,
+    -- Key of HMAC signature, it's needed in plain form.
+    secret text not null,
+    -- JSON array of sent events, created or deleted.
+    events text not null,
+    created_at timestamp not null
+);
+
+-- Events for webhooks, written in the same transaction as comment change.
+create table webhook_outbox (
+    id integer primary key,
+    webhook_id integer not null,
+    event text not null,
+    payload text not null,
+    attempts integer not null default 0,
+    -- Time of next try, it's moved forward while delivery is sent.
+    next_attempt_at timestamp not null,
+    delivered_at timestamp null,
+    last_error text null,
+    created_at timestamp not null,
+
+    foreign key (webhook_id) references webhooks(id) on delete cascade
+);
+
+create index webhook_outbox_pending_idx on webhook_outbox (next_attempt_at)
+    where delivered_at is null;
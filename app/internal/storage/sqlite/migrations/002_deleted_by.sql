@@ -0,0 +1,3 @@
+-- User who deleted comment, author can't restore deletion of moderator.
+alter table comments add column deleted_by integer null
+    references users(id) on delete set null;
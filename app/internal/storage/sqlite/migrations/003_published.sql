@@ -0,0 +1,7 @@
+-- Comment was approved once, so readers and webhooks already got it.
+alter table comments add column published integer not null default 0;
+
+-- Not approved comment with revisions most likely was sent back by edit.
+update comments set published = 1
+where status = 'approved'
+    or exists (select 1 from comment_revisions r where r.comment_id = comments.id);
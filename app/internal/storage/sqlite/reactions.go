@@ -0,0 +1,90 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"CommentTree/internal/entities/comment"
+	"CommentTree/pkg/errs"
+)
+
+// AddReaction sets reaction of user on comment, new vote replaces
+// opposite one. Setting existing reaction again isn't error.
+func (s *SQLite) AddReaction(commentID, userID int64, kind string) error {
+	const op = "internal.storage.sqlite.reactions.Add"
+
+	qVote := fmt.Sprintf(
+		"delete from %s where comment_id = ?1 and user_id = ?2 and kind = ?3",
+		ReactionsTable,
+	)
+	q := fmt.Sprintf(`
+		insert into %[1]s (comment_id, user_id, kind, created_at)
+		select id, ?2, ?3, ?4 from %[2]s
+		where id = ?1 and deleted_at is null and status = ?5
+		on conflict (comment_id, user_id, kind) do nothing;`,
+		ReactionsTable, CommentsTable,
+	)
+
+	ctx := context.Background()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	_, err = tx.ExecContext(ctx, qVote, commentID, userID, comment.OppositeVote(kind))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(
+		ctx, q, commentID, userID, kind, now(), comment.StatusApproved,
+	); err != nil {
+		return s.unwrapInternalError(op, err)
+	}
+
+	// Existing reaction isn't inserted again, so comment is checked apart.
+	var ok bool
+	err = tx.QueryRowContext(ctx, fmt.Sprintf(
+		"select exists (select 1 from %s where id = ?1 and deleted_at is null and status = ?2)",
+		CommentsTable,
+	), commentID, comment.StatusApproved).Scan(&ok)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	} else if !ok {
+		return errs.ErrDBNotAffected
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *SQLite) RemoveReaction(commentID, userID int64, kind string) error {
+	const op = "internal.storage.sqlite.reactions.Remove"
+
+	q := fmt.Sprintf(
+		"delete from %s where comment_id = ?1 and user_id = ?2 and kind = ?3",
+		ReactionsTable,
+	)
+
+	res, err := s.db.ExecContext(
+		context.Background(), q, commentID, userID, kind,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	} else if affected == 0 {
+		return errs.ErrDBNotAffected
+	}
+
+	return nil
+}
@@ -0,0 +1,202 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"CommentTree/internal/storage/textsearch"
+	"CommentTree/pkg/errs"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+const (
+	// Tables names, the same as in postgres.
+	CommentsTable  = "comments"
+	RevisionsTable = "comment_revisions"
+	UsersTable     = "users"
+	ReactionsTable = "reactions"
+	WebhooksTable  = "webhooks"
+	OutboxTable    = "webhook_outbox"
+
+	// Driver with search functions of textsearch.
+	DriverName = "sqlite3_comments"
+
+	// Connection params, foreign keys are off in sqlite by default.
+	ConnParams = "_foreign_keys=on&_busy_timeout=5000&_journal_mode=WAL"
+
+	// Sqlite has one writer, so one connection
+	// doesn't wait for locks of others.
+	MaxOpenConns = 1
+
+	// Timestamps are kept as text of fixed length,
+	// so they are compared in the right order.
+	TimeFormat = "2006-01-02 15:04:05.000000"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+func init() {
+	sql.Register(DriverName, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			err := conn.RegisterFunc("fulltext_rank", func(query, message string) float64 {
+				rank, _ := textsearch.Fulltext(query, message)
+				return rank
+			}, true)
+			if err != nil {
+				return err
+			}
+
+			err = conn.RegisterFunc("fulltext_snippet", func(query, message string) string {
+				_, snippet := textsearch.Fulltext(query, message)
+				return snippet
+			}, true)
+			if err != nil {
+				return err
+			}
+
+			err = conn.RegisterFunc("word_similarity", textsearch.WordSimilarity, true)
+			if err != nil {
+				return err
+			}
+
+			// Built-in lower and LIKE change case of ASCII letters only.
+			return conn.RegisterFunc("unicode_lower", strings.ToLower, true)
+		},
+	})
+}
+
+type SQLite struct {
+	db *sql.DB
+}
+
+// New opens database file path and applies new migrations to it.
+func New(path string) *SQLite {
+	db, err := sql.Open(DriverName, "file:"+path+"?"+ConnParams)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	db.SetMaxOpenConns(MaxOpenConns)
+
+	s := &SQLite{db: db}
+	if err := s.migrate(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	return s
+}
+
+func (s *SQLite) Shutdown() {
+	_ = s.db.Close()
+}
+
+// migrate runs migrations which are newer than user_version
+// of database, every migration goes in it's own transaction.
+func (s *SQLite) migrate() error {
+	const op = "internal.storage.sqlite.migrate"
+
+	ctx := context.Background()
+
+	var version int
+	err := s.db.QueryRowContext(ctx, "pragma user_version").Scan(&version)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	files, err := fs.Glob(migrations, "migrations/*.sql")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	slices.Sort(files)
+
+	for i, file := range files[min(version, len(files)):] {
+		q, err := migrations.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		_, err = tx.ExecContext(ctx, string(q))
+		if err == nil {
+			// Pragma doesn't take params.
+			_, err = tx.ExecContext(
+				ctx, fmt.Sprintf("pragma user_version = %d", version+i+1),
+			)
+		}
+		if err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("%s: %s: %w", op, file, err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *SQLite) unwrapInternalError(op string, err error) error {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		switch sqliteErr.ExtendedCode {
+		case sqlite3.ErrConstraintForeignKey:
+			return errs.ErrDBViolatesForeignKey
+		case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+			return errs.ErrDBAlreadyExists
+		}
+	}
+
+	return fmt.Errorf("%s: %w", op, err)
+}
+
+// now returns current time in TimeFormat.
+func now() string {
+	return formatTime(time.Now())
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(TimeFormat)
+}
+
+// nullTime scans timestamp column, driver returns it as time
+// when column type is known and as text in other cases.
+type nullTime struct {
+	Time  time.Time
+	Valid bool
+}
+
+func (n *nullTime) Scan(v any) error {
+	var err error
+	switch v := v.(type) {
+	case nil:
+		n.Time, n.Valid = time.Time{}, false
+		return nil
+	case time.Time:
+		n.Time = v.UTC()
+	case string:
+		n.Time, err = time.ParseInLocation(TimeFormat, v, time.UTC)
+	case []byte:
+		n.Time, err = time.ParseInLocation(TimeFormat, string(v), time.UTC)
+	default:
+		err = fmt.Errorf("can't scan %T to time", v)
+	}
+
+	n.Valid = err == nil
+	return err
+}
@@ -0,0 +1,260 @@
+package sqlite
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"CommentTree/internal/entities/comment"
+	"CommentTree/internal/storage/storagetest"
+	"CommentTree/pkg/errs"
+)
+
+// open creates database in temporary directory of the test.
+func open(t *testing.T) *SQLite {
+	t.Helper()
+
+	s := New(filepath.Join(t.TempDir(), "comments.db"))
+	t.Cleanup(s.Shutdown)
+
+	return s
+}
+
+// fill creates n root comments of thread "t" with messages "comment i".
+func fill(t *testing.T, s *SQLite, n int) []int64 {
+	t.Helper()
+
+	ids := make([]int64, 0, n)
+	for i := range n {
+		id, err := s.CreateComment(comment.Comment{
+			Message:   fmt.Sprintf("comment %d", i),
+			ThreadKey: "t",
+		})
+		if err != nil {
+			t.Fatalf("CreateComment() err = %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+func TestSQLite_New(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "comments.db")
+
+	s := New(path)
+	id := fill(t, s, 1)[0]
+	s.Shutdown()
+
+	// Reopening must skip applied migrations and keep data.
+	s = New(path)
+	defer s.Shutdown()
+
+	if _, err := s.Parent(id); err != nil {
+		t.Errorf("Parent() err = %v", err)
+	}
+}
+
+func TestSQLite_CreateComment(t *testing.T) {
+	s := open(t)
+	root := fill(t, s, 1)[0]
+
+	tests := []struct {
+		name    string // description of this test case
+		parent  int64
+		wantErr error
+	}{
+		{
+			name:   "reply",
+			parent: root,
+		},
+		{
+			name:    "missing parent",
+			parent:  root + 100,
+			wantErr: errs.ErrDBViolatesForeignKey,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := s.CreateComment(comment.Comment{Message: "reply", ParentID: tt.parent})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateComment() err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			got, err := s.Parent(id)
+			if err != nil {
+				t.Fatalf("Parent() err = %v", err)
+			}
+			if got.ParentID != tt.parent || got.ThreadKey != "t" {
+				t.Errorf("Parent() = %v, want reply in thread of parent", got)
+			}
+		})
+	}
+}
+
+func TestSQLite_Childs(t *testing.T) {
+	s := open(t)
+	ids := fill(t, s, comment.PageElements+2)
+	if _, err := s.CreateComment(comment.Comment{Message: "100% done_", ThreadKey: "t"}); err != nil {
+		t.Fatalf("CreateComment() err = %v", err)
+	}
+
+	tests := []struct {
+		name      string // description of this test case
+		opts      *comment.GetterOpts
+		wantLen   int
+		wantFirst int64
+		wantNext  bool
+	}{
+		{
+			name:      "first page",
+			opts:      &comment.GetterOpts{},
+			wantLen:   comment.PageElements,
+			wantFirst: ids[0],
+			wantNext:  true,
+		},
+		{
+			name:      "newest",
+			opts:      &comment.GetterOpts{Sort: comment.SortNewest, Substr: "comment"},
+			wantLen:   comment.PageElements,
+			wantFirst: ids[len(ids)-1],
+			wantNext:  true,
+		},
+		{
+			name:      "substr",
+			opts:      &comment.GetterOpts{Substr: "comment 11"},
+			wantLen:   1,
+			wantFirst: ids[11],
+			wantNext:  false,
+		},
+		{
+			name:    "wildcards are escaped",
+			opts:    &comment.GetterOpts{Substr: "%"},
+			wantLen: 1,
+		},
+		{
+			name: "other thread",
+			opts: &comment.GetterOpts{ThreadKey: "other"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Childs(0, tt.opts)
+			if err != nil {
+				t.Fatalf("Childs() err = %v", err)
+			}
+			if len(got) != tt.wantLen {
+				t.Fatalf("Childs() len = %v, want %v", len(got), tt.wantLen)
+			}
+			if len(got) == 0 || tt.wantFirst == 0 {
+				return
+			}
+			if got[0].ID != tt.wantFirst {
+				t.Errorf("Childs() first = %v, want %v", got[0].ID, tt.wantFirst)
+			}
+			if last := got[len(got)-1]; last.HaveNext != tt.wantNext {
+				t.Errorf("Childs() HaveNext = %v, want %v", last.HaveNext, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestSQLite_Childs_Search(t *testing.T) {
+	s := open(t)
+	for _, msg := range []string{
+		"The cats are sleeping",
+		"Dogs are barking",
+		"Hello world",
+	} {
+		if _, err := s.CreateComment(comment.Comment{Message: msg, ThreadKey: "t"}); err != nil {
+			t.Fatalf("CreateComment() err = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name string // description of this test case
+		mode string
+		text string
+		want string
+	}{
+		{
+			name: "fulltext with stemming",
+			mode: comment.SearchFulltext,
+			text: "cat",
+			want: "The cats are sleeping",
+		},
+		{
+			name: "fuzzy with typo",
+			mode: comment.SearchFuzzy,
+			text: "helo",
+			want: "Hello world",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Childs(0, &comment.GetterOpts{
+				SearchGlobal: true,
+				Substr:       tt.text,
+				SearchMode:   tt.mode,
+			})
+			if err != nil {
+				t.Fatalf("Childs() err = %v", err)
+			}
+			if len(got) != 1 || got[0].Message != tt.want {
+				t.Errorf("Childs() = %v, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSQLite_DeleteComment(t *testing.T) {
+	tests := []struct {
+		name    string // description of this test case
+		cascade bool
+		wantErr error
+	}{
+		{
+			name:    "soft",
+			cascade: false,
+		},
+		{
+			name:    "cascade",
+			cascade: true,
+			wantErr: errs.ErrDBNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := open(t)
+			root := fill(t, s, 1)[0]
+			reply, err := s.CreateComment(comment.Comment{Message: "reply", ParentID: root})
+			if err != nil {
+				t.Fatalf("CreateComment() err = %v", err)
+			}
+
+			if err := s.DeleteComment(root, 0, tt.cascade); err != nil {
+				t.Fatalf("DeleteComment() err = %v", err)
+			}
+
+			_, err = s.Parent(reply)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Parent() err = %v, want %v", err, tt.wantErr)
+			}
+
+			got, err := s.Parent(root)
+			if err == nil && (!got.Deleted || got.Message != comment.DeletedMessage) {
+				t.Errorf("Parent() = %v, want tombstone", got)
+			}
+		})
+	}
+}
+
+func TestSQLite_Conformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storagetest.DB {
+		return open(t)
+	})
+}
@@ -0,0 +1,53 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"CommentTree/internal/entities/user"
+	"CommentTree/pkg/errs"
+)
+
+func (s *SQLite) CreateUser(u user.User, tokenHash string) (int64, error) {
+	const op = "internal.storage.sqlite.users.Create"
+
+	q := fmt.Sprintf(
+		"insert into %s (name, token_hash, role, created_at) values (?1, ?2, ?3, ?4);",
+		UsersTable,
+	)
+
+	res, err := s.db.ExecContext(
+		context.Background(), q, u.Name, tokenHash, u.Role, now(),
+	)
+	if err != nil {
+		return 0, s.unwrapInternalError(op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (s *SQLite) UserByToken(tokenHash string) (user.User, error) {
+	const op = "internal.storage.sqlite.users.byToken"
+
+	q := fmt.Sprintf(
+		"select id, name, role from %s where token_hash = ?1", UsersTable,
+	)
+
+	var u user.User
+	err := s.db.QueryRowContext(context.Background(), q, tokenHash).
+		Scan(&u.ID, &u.Name, &u.Role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return user.User{}, errs.ErrDBNotFound
+	} else if err != nil {
+		return user.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return u, nil
+}
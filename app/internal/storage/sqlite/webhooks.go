@@ -0,0 +1,254 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"CommentTree/internal/entities/comment"
+	"CommentTree/internal/entities/event"
+	"CommentTree/internal/entities/webhook"
+	"CommentTree/pkg/errs"
+)
+
+// enqueueWebhooks writes event typ about comment id to outbox for every
+// webhook which waits for it, it should go in transaction of the change.
+// Events about not approved comments aren't written.
+func enqueueWebhooks(ctx context.Context, tx *sql.Tx, typ string, id int64) error {
+	qComment := fmt.Sprintf(
+		`select coalesce(parent_id, 0), thread_key, coalesce(author_id, 0), message
+		from %s where id = ?1 and status = ?2`,
+		CommentsTable,
+	)
+
+	p := webhook.Payload{Event: typ, CommentID: id, OccurredAt: time.Now()}
+	var message string
+	err := tx.QueryRowContext(ctx, qComment, id, comment.StatusApproved).
+		Scan(&p.ParentID, &p.ThreadKey, &p.AuthorID, &message)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if typ == event.TypeCreated {
+		p.Message = message
+	}
+
+	payload, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	q := fmt.Sprintf(`
+		insert into %[1]s (webhook_id, event, payload, next_attempt_at, created_at)
+		select w.id, ?1, ?2, ?3, ?3 from %[2]s w
+		where exists (select 1 from json_each(w.events) where value = ?1)
+		order by w.id;`,
+		OutboxTable, WebhooksTable,
+	)
+
+	_, err = tx.ExecContext(ctx, q, typ, string(payload), formatTime(p.OccurredAt))
+	return err
+}
+
+func (s *SQLite) CreateWebhook(w webhook.Webhook) (webhook.Webhook, error) {
+	const op = "internal.storage.sqlite.webhooks.Create"
+
+	q := fmt.Sprintf(
+		"insert into %s (url, secret, events, created_at) values (?1, ?2, ?3, ?4);",
+		WebhooksTable,
+	)
+
+	events, err := json.Marshal(w.Events)
+	if err != nil {
+		return webhook.Webhook{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	t := time.Now()
+	res, err := s.db.ExecContext(
+		context.Background(), q, w.URL, w.Secret, string(events), formatTime(t),
+	)
+	if err != nil {
+		return webhook.Webhook{}, s.unwrapInternalError(op, err)
+	}
+
+	if w.ID, err = res.LastInsertId(); err != nil {
+		return webhook.Webhook{}, fmt.Errorf("%s: %w", op, err)
+	}
+	// The same precision as in table.
+	w.CreatedAt = t.UTC().Truncate(time.Microsecond)
+
+	return w, nil
+}
+
+// Webhooks returns all webhooks without secrets.
+func (s *SQLite) Webhooks() ([]webhook.Webhook, error) {
+	const op = "internal.storage.sqlite.webhooks.list"
+
+	q := fmt.Sprintf(
+		"select id, url, events, created_at from %s order by id;",
+		WebhooksTable,
+	)
+
+	rows, err := s.db.QueryContext(context.Background(), q)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	hooks := []webhook.Webhook{}
+	for rows.Next() {
+		var w webhook.Webhook
+		var events string
+		var createdAt nullTime
+
+		if err := rows.Scan(&w.ID, &w.URL, &events, &createdAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if err := json.Unmarshal([]byte(events), &w.Events); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		w.CreatedAt = createdAt.Time
+
+		hooks = append(hooks, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return hooks, nil
+}
+
+// DeleteWebhook removes webhook with it's not sent deliveries.
+func (s *SQLite) DeleteWebhook(id int64) error {
+	const op = "internal.storage.sqlite.webhooks.Delete"
+
+	q := fmt.Sprintf("delete from %s where id = ?1", WebhooksTable)
+
+	res, err := s.db.ExecContext(context.Background(), q, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	} else if affected == 0 {
+		return errs.ErrDBNotAffected
+	}
+
+	return nil
+}
+
+// ClaimDeliveries takes up to limit due deliveries and hides them from
+// other dispatchers for lease. Deliveries which were tried maxAttempts
+// times stay in outbox with their last error.
+func (s *SQLite) ClaimDeliveries(limit int, lease time.Duration, maxAttempts int) ([]webhook.Delivery, error) {
+	const op = "internal.storage.sqlite.webhooks.ClaimDeliveries"
+
+	qDue := fmt.Sprintf(`
+		select o.id, o.webhook_id, w.url, w.secret, o.event, o.payload, o.attempts + 1
+		from %[1]s o join %[2]s w on w.id = o.webhook_id
+		where o.delivered_at is null and o.next_attempt_at <= ?2
+			and o.attempts < ?3
+		order by o.id limit ?1;`,
+		OutboxTable, WebhooksTable,
+	)
+	q := fmt.Sprintf(
+		"update %s set attempts = attempts + 1, next_attempt_at = ?2 where id = ?1",
+		OutboxTable,
+	)
+
+	ctx := context.Background()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	t := time.Now()
+	rows, err := tx.QueryContext(ctx, qDue, limit, formatTime(t), maxAttempts)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	deliveries := []webhook.Delivery{}
+	for rows.Next() {
+		var d webhook.Delivery
+		var payload string
+
+		err := rows.Scan(
+			&d.ID, &d.WebhookID, &d.URL, &d.Secret,
+			&d.Event, &payload, &d.Attempts,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		d.Payload = []byte(payload)
+
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	_ = rows.Close()
+
+	// Sqlite has one writer, so nobody takes them in between.
+	for _, d := range deliveries {
+		if _, err := tx.ExecContext(ctx, q, d.ID, formatTime(t.Add(lease))); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deliveries, nil
+}
+
+func (s *SQLite) CompleteDelivery(id int64) error {
+	const op = "internal.storage.sqlite.webhooks.CompleteDelivery"
+
+	q := fmt.Sprintf(
+		"update %s set delivered_at = ?2, last_error = null where id = ?1",
+		OutboxTable,
+	)
+
+	if _, err := s.db.ExecContext(context.Background(), q, id, now()); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// FailDelivery saves error of delivery, it's tried again at retryAt.
+func (s *SQLite) FailDelivery(id int64, reason string, retryAt time.Time) error {
+	const op = "internal.storage.sqlite.webhooks.FailDelivery"
+
+	q := fmt.Sprintf(
+		"update %s set last_error = ?2, next_attempt_at = ?3 where id = ?1",
+		OutboxTable,
+	)
+
+	_, err := s.db.ExecContext(
+		context.Background(), q, id, reason, formatTime(retryAt),
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -4,19 +4,37 @@ import (
 	"errors"
 
 	"CommentTree/internal/entities/comment"
+	"CommentTree/internal/entities/user"
+	"CommentTree/internal/entities/webhook"
 )
 
 var (
 	ErrNotAffected     = errors.New("not affected")
 	ErrNotFound        = errors.New("not found")
 	ErrWrongForeignKey = errors.New("wrong foreign key")
+	ErrAlreadyExists   = errors.New("already exists")
 )
 
 type db interface {
 	CreateComment(c comment.Comment) (int64, error)
 	Parent(id int64) (comment.Comment, error)
+	Single(id int64, anyStatus bool) (comment.SingleView, error)
+	Path(id int64, anyStatus bool) ([]comment.Comment, error)
 	Childs(parentID int64, opts *comment.GetterOpts) ([]comment.Comment, error)
-	DeleteComment(id int64) error
+	Tree(id int64, opts *comment.TreeOpts) (*comment.TreeNode, error)
+	EditComment(id int64, message, status string) error
+	Revisions(id int64, anyStatus bool) ([]comment.Revision, error)
+	DeleteComment(id, userID int64, cascade bool) error
+	RestoreComment(id int64) error
+	AddReaction(commentID, userID int64, kind string) error
+	RemoveReaction(commentID, userID int64, kind string) error
+	SetStatus(id int64, status string) (bool, error)
+	CreateWebhook(w webhook.Webhook) (webhook.Webhook, error)
+	Webhooks() ([]webhook.Webhook, error)
+	DeleteWebhook(id int64) error
+
+	CreateUser(u user.User, tokenHash string) (int64, error)
+	UserByToken(tokenHash string) (user.User, error)
 
 	Shutdown()
 }
@@ -0,0 +1,768 @@
+package storagetest
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"CommentTree/internal/entities/comment"
+	"CommentTree/internal/entities/user"
+	"CommentTree/pkg/errs"
+)
+
+// DB is part of storage db interface which is checked by Run.
+type DB interface {
+	CreateComment(c comment.Comment) (int64, error)
+	Parent(id int64) (comment.Comment, error)
+	Single(id int64, anyStatus bool) (comment.SingleView, error)
+	Path(id int64, anyStatus bool) ([]comment.Comment, error)
+	Childs(parentID int64, opts *comment.GetterOpts) ([]comment.Comment, error)
+	Tree(id int64, opts *comment.TreeOpts) (*comment.TreeNode, error)
+	EditComment(id int64, message, status string) error
+	Revisions(id int64, anyStatus bool) ([]comment.Revision, error)
+	DeleteComment(id, userID int64, cascade bool) error
+	RestoreComment(id int64) error
+	AddReaction(commentID, userID int64, kind string) error
+	SetStatus(id int64, status string) (bool, error)
+	CreateUser(u user.User, tokenHash string) (int64, error)
+	UserByToken(tokenHash string) (user.User, error)
+	Shutdown()
+}
+
+// Run checks that db behaves like postgres storage, open
+// returns new empty db for every test.
+func Run(t *testing.T, open func(t *testing.T) DB) {
+	tests := []struct {
+		name string // description of this test case
+		test func(t *testing.T, db DB)
+	}{
+		{name: "create and parent", test: testCreate},
+		{name: "foreign keys", test: testForeignKeys},
+		{name: "pages", test: testPages},
+		{name: "cursor", test: testCursor},
+		{name: "sorts", test: testSorts},
+		{name: "substr search", test: testSubstr},
+		{name: "ranked search", test: testRankedSearch},
+		{name: "soft delete", test: testSoftDelete},
+		{name: "cascade delete", test: testCascadeDelete},
+		{name: "counts", test: testCounts},
+		{name: "path and tree", test: testTree},
+		{name: "edit", test: testEdit},
+		{name: "moderation", test: testModeration},
+		{name: "hidden status", test: testHiddenStatus},
+		{name: "reactions", test: testReactions},
+		{name: "users", test: testUsers},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := open(t)
+			t.Cleanup(db.Shutdown)
+
+			tt.test(t, db)
+		})
+	}
+}
+
+// create saves comment and fails test on error.
+func create(t *testing.T, db DB, c comment.Comment) int64 {
+	t.Helper()
+
+	if c.ParentID == 0 && c.ThreadKey == "" {
+		c.ThreadKey = "thread"
+	}
+
+	id, err := db.CreateComment(c)
+	if err != nil {
+		t.Fatalf("CreateComment() err = %v", err)
+	}
+
+	return id
+}
+
+// roots creates n root comments with messages "comment i".
+func roots(t *testing.T, db DB, n int) []int64 {
+	t.Helper()
+
+	ids := make([]int64, 0, n)
+	for i := range n {
+		ids = append(ids, create(t, db, comment.Comment{
+			Message: fmt.Sprintf("comment %d", i),
+		}))
+	}
+
+	return ids
+}
+
+func childs(t *testing.T, db DB, parentID int64, opts *comment.GetterOpts) []comment.Comment {
+	t.Helper()
+
+	coms, err := db.Childs(parentID, opts)
+	if err != nil {
+		t.Fatalf("Childs() err = %v", err)
+	}
+
+	return coms
+}
+
+func parent(t *testing.T, db DB, id int64) comment.Comment {
+	t.Helper()
+
+	c, err := db.Parent(id)
+	if err != nil {
+		t.Fatalf("Parent() err = %v", err)
+	}
+
+	return c
+}
+
+func ids(coms []comment.Comment) []int64 {
+	res := make([]int64, 0, len(coms))
+	for _, c := range coms {
+		res = append(res, c.ID)
+	}
+
+	return res
+}
+
+func testCreate(t *testing.T, db DB) {
+	root := create(t, db, comment.Comment{Message: "root", ThreadKey: "article-1"})
+	reply := create(t, db, comment.Comment{
+		Message: "reply", ParentID: root, ThreadKey: "other",
+	})
+
+	got := parent(t, db, root)
+	if got.Message != "root" || got.ThreadKey != "article-1" ||
+		got.Status != comment.StatusApproved || got.CreatedAt.IsZero() {
+		t.Errorf("Parent() = %+v, want saved root", got)
+	}
+
+	got = parent(t, db, reply)
+	if got.ParentID != root || got.ThreadKey != "article-1" {
+		t.Errorf("Parent() = %+v, want reply in thread of root", got)
+	}
+
+	if _, err := db.Parent(reply + 100); !errors.Is(err, errs.ErrDBNotFound) {
+		t.Errorf("Parent() err = %v, want %v", err, errs.ErrDBNotFound)
+	}
+}
+
+func testForeignKeys(t *testing.T, db DB) {
+	root := create(t, db, comment.Comment{Message: "root"})
+	deleted := create(t, db, comment.Comment{Message: "deleted"})
+	if err := db.DeleteComment(deleted, 0, false); err != nil {
+		t.Fatalf("DeleteComment() err = %v", err)
+	}
+	pending := create(t, db, comment.Comment{
+		Message: "pending", Status: comment.StatusPending,
+	})
+
+	tests := []struct {
+		name string // description of this test case
+		c    comment.Comment
+	}{
+		{
+			name: "missing parent",
+			c:    comment.Comment{Message: "hi", ParentID: root + 100},
+		},
+		{
+			name: "deleted parent",
+			c:    comment.Comment{Message: "hi", ParentID: deleted},
+		},
+		{
+			name: "pending parent",
+			c:    comment.Comment{Message: "hi", ParentID: pending},
+		},
+		{
+			name: "missing author",
+			c:    comment.Comment{Message: "hi", ParentID: root, AuthorID: 100},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := db.CreateComment(tt.c)
+			if !errors.Is(err, errs.ErrDBViolatesForeignKey) {
+				t.Errorf("CreateComment() err = %v, want %v", err, errs.ErrDBViolatesForeignKey)
+			}
+		})
+	}
+}
+
+func testPages(t *testing.T, db DB) {
+	all := roots(t, db, comment.PageElements+2)
+
+	tests := []struct {
+		name     string // description of this test case
+		opts     *comment.GetterOpts
+		want     []int64
+		wantNext bool
+	}{
+		{
+			name:     "without opts",
+			opts:     nil,
+			want:     all[:comment.PageElements],
+			wantNext: true,
+		},
+		{
+			name:     "first page",
+			opts:     &comment.GetterOpts{Page: 1},
+			want:     all[:comment.PageElements],
+			wantNext: true,
+		},
+		{
+			name:     "last page",
+			opts:     &comment.GetterOpts{Page: 2},
+			want:     all[comment.PageElements:],
+			wantNext: false,
+		},
+		{
+			name: "after last page",
+			opts: &comment.GetterOpts{Page: 3},
+			want: []int64{},
+		},
+		{
+			name: "other thread",
+			opts: &comment.GetterOpts{ThreadKey: "other"},
+			want: []int64{},
+		},
+		{
+			name: "pending",
+			opts: &comment.GetterOpts{Status: comment.StatusPending},
+			want: []int64{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := childs(t, db, 0, tt.opts)
+			if fmt.Sprint(ids(got)) != fmt.Sprint(tt.want) {
+				t.Fatalf("Childs() = %v, want %v", ids(got), tt.want)
+			}
+
+			for i, c := range got {
+				wantNext := i < len(got)-1 || tt.wantNext
+				if c.HaveNext != wantNext {
+					t.Errorf("Childs()[%d].HaveNext = %v, want %v", i, c.HaveNext, wantNext)
+				}
+			}
+		})
+	}
+}
+
+func testCursor(t *testing.T, db DB) {
+	all := roots(t, db, comment.PageElements+2)
+
+	tests := []struct {
+		name string // description of this test case
+		sort string
+		want []int64
+	}{
+		{
+			name: "oldest",
+			sort: comment.SortOldest,
+			want: all[comment.PageElements:],
+		},
+		{
+			name: "newest",
+			sort: comment.SortNewest,
+			want: []int64{all[1], all[0]},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first := childs(t, db, 0, &comment.GetterOpts{Sort: tt.sort})
+			cursor := comment.NewCursor(first[len(first)-1], tt.sort)
+
+			got := childs(t, db, 0, &comment.GetterOpts{Sort: tt.sort, After: &cursor})
+			if fmt.Sprint(ids(got)) != fmt.Sprint(tt.want) {
+				t.Errorf("Childs() = %v, want %v", ids(got), tt.want)
+			}
+			if len(got) > 0 && got[len(got)-1].HaveNext {
+				t.Errorf("Childs() last HaveNext = true, want false")
+			}
+		})
+	}
+}
+
+func testSorts(t *testing.T, db DB) {
+	all := roots(t, db, 3)
+	for i, n := range []int{0, 2, 1} {
+		for range n {
+			create(t, db, comment.Comment{Message: "reply", ParentID: all[i]})
+		}
+	}
+	voter, err := db.CreateUser(user.User{Name: "bob", Role: user.RoleUser}, "bob")
+	if err != nil {
+		t.Fatalf("CreateUser() err = %v", err)
+	}
+	if err := db.AddReaction(all[0], voter, comment.ReactionUp); err != nil {
+		t.Fatalf("AddReaction() err = %v", err)
+	}
+
+	tests := []struct {
+		name string // description of this test case
+		sort string
+		want []int64
+	}{
+		{
+			name: "newest",
+			sort: comment.SortNewest,
+			want: []int64{all[2], all[1], all[0]},
+		},
+		{
+			name: "most replied",
+			sort: comment.SortMostReplied,
+			want: []int64{all[1], all[2], all[0]},
+		},
+		{
+			// Equal scores go from newest id.
+			name: "top",
+			sort: comment.SortTop,
+			want: []int64{all[0], all[2], all[1]},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := childs(t, db, 0, &comment.GetterOpts{Sort: tt.sort})
+			if fmt.Sprint(ids(got)) != fmt.Sprint(tt.want) {
+				t.Fatalf("Childs() = %v, want %v", ids(got), tt.want)
+			}
+
+			cursor := comment.NewCursor(got[0], tt.sort)
+			got = childs(t, db, 0, &comment.GetterOpts{Sort: tt.sort, After: &cursor})
+			if fmt.Sprint(ids(got)) != fmt.Sprint(tt.want[1:]) {
+				t.Errorf("Childs() after cursor = %v, want %v", ids(got), tt.want[1:])
+			}
+		})
+	}
+}
+
+func testSubstr(t *testing.T, db DB) {
+	root := create(t, db, comment.Comment{Message: "Hello world"})
+	reply := create(t, db, comment.Comment{Message: "hello 100%", ParentID: root})
+	deleted := create(t, db, comment.Comment{Message: "hello again", ParentID: root})
+	if err := db.DeleteComment(deleted, 0, false); err != nil {
+		t.Fatalf("DeleteComment() err = %v", err)
+	}
+
+	tests := []struct {
+		name     string // description of this test case
+		parentID int64
+		opts     *comment.GetterOpts
+		want     []int64
+	}{
+		{
+			name: "only roots",
+			opts: &comment.GetterOpts{Substr: "hello"},
+			want: []int64{},
+		},
+		{
+			name: "global",
+			opts: &comment.GetterOpts{Substr: "hello", SearchGlobal: true},
+			want: []int64{reply},
+		},
+		{
+			name: "case sensitive",
+			opts: &comment.GetterOpts{Substr: "Hello", SearchGlobal: true},
+			want: []int64{root},
+		},
+		{
+			name:     "wildcard as is",
+			parentID: root,
+			opts:     &comment.GetterOpts{Substr: "%"},
+			want:     []int64{reply},
+		},
+		{
+			name:     "deleted isn't found",
+			parentID: root,
+			opts:     &comment.GetterOpts{Substr: "again"},
+			want:     []int64{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := childs(t, db, tt.parentID, tt.opts)
+			if fmt.Sprint(ids(got)) != fmt.Sprint(tt.want) {
+				t.Errorf("Childs() = %v, want %v", ids(got), tt.want)
+			}
+		})
+	}
+}
+
+func testRankedSearch(t *testing.T, db DB) {
+	cats := create(t, db, comment.Comment{Message: "The cats are sleeping"})
+	hello := create(t, db, comment.Comment{Message: "Hello world"})
+	create(t, db, comment.Comment{Message: "Dogs are barking"})
+	sights := create(t, db, comment.Comment{Message: "Достопримечательность"})
+
+	t.Run("fulltext", func(t *testing.T) {
+		got := childs(t, db, 0, &comment.GetterOpts{
+			Substr: "cat", SearchMode: comment.SearchFulltext,
+		})
+		if len(got) != 1 || got[0].ID != cats {
+			t.Fatalf("Childs() = %v, want %v", ids(got), cats)
+		}
+		if got[0].Rank <= 0 || !strings.Contains(got[0].Snippet, "<b>") {
+			t.Errorf("Childs() rank = %v, snippet = %q, want found words",
+				got[0].Rank, got[0].Snippet)
+		}
+	})
+
+	t.Run("fuzzy", func(t *testing.T) {
+		got := childs(t, db, 0, &comment.GetterOpts{
+			Substr: "helo", SearchMode: comment.SearchFuzzy,
+		})
+		if len(got) != 1 || got[0].ID != hello {
+			t.Fatalf("Childs() = %v, want %v", ids(got), hello)
+		}
+		if got[0].Similarity <= 0 {
+			t.Errorf("Childs() similarity = %v, want positive", got[0].Similarity)
+		}
+	})
+
+	// Part of word isn't similar, it's found as substring in any case
+	// of any alphabet, like ILIKE of postgres does.
+	t.Run("fuzzy substring", func(t *testing.T) {
+		got := childs(t, db, 0, &comment.GetterOpts{
+			Substr: "ИМЕЧ", SearchMode: comment.SearchFuzzy,
+		})
+		if len(got) != 1 || got[0].ID != sights {
+			t.Fatalf("Childs() = %v, want %v", ids(got), sights)
+		}
+	})
+}
+
+func testSoftDelete(t *testing.T, db DB) {
+	root := create(t, db, comment.Comment{Message: "root"})
+	reply := create(t, db, comment.Comment{Message: "reply", ParentID: root})
+
+	moderatorID, err := db.CreateUser(user.User{Name: "mod", Role: user.RoleModerator}, "hash")
+	if err != nil {
+		t.Fatalf("CreateUser() err = %v", err)
+	}
+
+	if err := db.DeleteComment(root, moderatorID, false); err != nil {
+		t.Fatalf("DeleteComment() err = %v", err)
+	}
+	if err := db.DeleteComment(root, moderatorID, false); !errors.Is(err, errs.ErrDBNotAffected) {
+		t.Errorf("DeleteComment() again err = %v, want %v", err, errs.ErrDBNotAffected)
+	}
+
+	got := parent(t, db, root)
+	if !got.Deleted || got.Message != comment.DeletedMessage || got.DeletedBy != moderatorID {
+		t.Errorf("Parent() = %+v, want tombstone deleted by %v", got, moderatorID)
+	}
+	// Tombstone keeps it's place and replies.
+	if got := childs(t, db, 0, nil); len(got) != 1 || !got[0].Deleted {
+		t.Errorf("Childs() = %v, want tombstone", got)
+	}
+	if got := childs(t, db, root, nil); len(got) != 1 || got[0].ID != reply {
+		t.Errorf("Childs() = %v, want reply %v", ids(got), reply)
+	}
+
+	if err := db.RestoreComment(root); err != nil {
+		t.Fatalf("RestoreComment() err = %v", err)
+	}
+	if got := parent(t, db, root); got.Deleted || got.DeletedBy != 0 || got.Message != "root" {
+		t.Errorf("Parent() = %+v, want restored comment", got)
+	}
+	if err := db.RestoreComment(root); !errors.Is(err, errs.ErrDBNotAffected) {
+		t.Errorf("RestoreComment() again err = %v, want %v", err, errs.ErrDBNotAffected)
+	}
+}
+
+func testCascadeDelete(t *testing.T, db DB) {
+	root := create(t, db, comment.Comment{Message: "root"})
+	reply := create(t, db, comment.Comment{Message: "reply", ParentID: root})
+	deep := create(t, db, comment.Comment{Message: "deep", ParentID: reply})
+
+	if err := db.DeleteComment(root, 0, true); err != nil {
+		t.Fatalf("DeleteComment() err = %v", err)
+	}
+
+	for _, id := range []int64{root, reply, deep} {
+		if _, err := db.Parent(id); !errors.Is(err, errs.ErrDBNotFound) {
+			t.Errorf("Parent(%d) err = %v, want %v", id, err, errs.ErrDBNotFound)
+		}
+	}
+	if err := db.DeleteComment(root, 0, true); !errors.Is(err, errs.ErrDBNotAffected) {
+		t.Errorf("DeleteComment() again err = %v, want %v", err, errs.ErrDBNotAffected)
+	}
+}
+
+func testCounts(t *testing.T, db DB) {
+	root := create(t, db, comment.Comment{Message: "root"})
+	reply := create(t, db, comment.Comment{Message: "reply", ParentID: root})
+	create(t, db, comment.Comment{Message: "deep", ParentID: reply})
+	// Readers don't see pending replies, so they aren't counted.
+	create(t, db, comment.Comment{
+		Message: "pending", ParentID: root, Status: comment.StatusPending,
+	})
+
+	got := parent(t, db, root)
+	if got.ReplyCount != 1 || got.DescendantCount != 2 {
+		t.Errorf("Parent() replies = %v, descendants = %v, want 1 and 2",
+			got.ReplyCount, got.DescendantCount)
+	}
+
+	all := childs(t, db, root, &comment.GetterOpts{AnyStatus: true})
+	if len(all) != 2 {
+		t.Errorf("Childs() = %v, want replies in any status", ids(all))
+	}
+}
+
+func testTree(t *testing.T, db DB) {
+	root := create(t, db, comment.Comment{Message: "root"})
+	first := create(t, db, comment.Comment{Message: "first", ParentID: root})
+	create(t, db, comment.Comment{Message: "second", ParentID: root})
+	deep := create(t, db, comment.Comment{Message: "deep", ParentID: first})
+	deeper := create(t, db, comment.Comment{Message: "deeper", ParentID: deep})
+
+	path, err := db.Path(deeper, false)
+	if err != nil {
+		t.Fatalf("Path() err = %v", err)
+	}
+	if want := []int64{root, first, deep, deeper}; fmt.Sprint(ids(path)) != fmt.Sprint(want) {
+		t.Errorf("Path() = %v, want %v", ids(path), want)
+	}
+
+	tree, err := db.Tree(root, &comment.TreeOpts{MaxDepth: 2, Limit: 1})
+	if err != nil {
+		t.Fatalf("Tree() err = %v", err)
+	}
+	if len(tree.Replies) != 1 || tree.Replies[0].ID != first || !tree.Replies[0].HaveNext {
+		t.Fatalf("Tree() replies = %+v, want only first with HaveNext", tree.Replies)
+	}
+	if r := tree.Replies[0].Replies; len(r) != 1 || r[0].ID != deep || len(r[0].Replies) != 0 {
+		t.Errorf("Tree() second level = %+v, want deep without replies", r)
+	}
+
+	_, err = db.Tree(deeper+100, &comment.TreeOpts{MaxDepth: 1, Limit: 1})
+	if !errors.Is(err, errs.ErrDBNotFound) {
+		t.Errorf("Tree() err = %v, want %v", err, errs.ErrDBNotFound)
+	}
+}
+
+func testEdit(t *testing.T, db DB) {
+	id := create(t, db, comment.Comment{Message: "first"})
+
+	if err := db.EditComment(id, "second", ""); err != nil {
+		t.Fatalf("EditComment() err = %v", err)
+	}
+
+	got := parent(t, db, id)
+	if got.Message != "second" || got.EditedAt == nil || got.Status != comment.StatusApproved {
+		t.Errorf("Parent() = %+v, want edited approved comment", got)
+	}
+
+	revs, err := db.Revisions(id, false)
+	if err != nil {
+		t.Fatalf("Revisions() err = %v", err)
+	}
+	if len(revs) != 1 || revs[0].Message != "first" || revs[0].CommentID != id {
+		t.Errorf("Revisions() = %+v, want first message", revs)
+	}
+
+	// History of deleted comment is hidden and it can't be edited.
+	if err := db.DeleteComment(id, 0, false); err != nil {
+		t.Fatalf("DeleteComment() err = %v", err)
+	}
+	if err := db.EditComment(id, "third", ""); !errors.Is(err, errs.ErrDBNotAffected) {
+		t.Errorf("EditComment() err = %v, want %v", err, errs.ErrDBNotAffected)
+	}
+	if revs, err := db.Revisions(id, false); err != nil || len(revs) != 0 {
+		t.Errorf("Revisions() = %v, %v, want empty", revs, err)
+	}
+	if _, err := db.Revisions(id+100, false); !errors.Is(err, errs.ErrDBNotFound) {
+		t.Errorf("Revisions() err = %v, want %v", err, errs.ErrDBNotFound)
+	}
+
+	// Flagged edit goes back to moderation.
+	flagged := create(t, db, comment.Comment{Message: "clean"})
+	if err := db.EditComment(flagged, "spam", comment.StatusPending); err != nil {
+		t.Fatalf("EditComment() err = %v", err)
+	}
+	if got := parent(t, db, flagged); got.Message != "spam" || got.Status != comment.StatusPending {
+		t.Errorf("Parent() = %+v, want pending edited comment", got)
+	}
+}
+
+func testModeration(t *testing.T, db DB) {
+	id := create(t, db, comment.Comment{
+		Message: "pending", Status: comment.StatusPending,
+	})
+
+	if got := childs(t, db, 0, nil); len(got) != 0 {
+		t.Errorf("Childs() = %v, want pending comment hidden", ids(got))
+	}
+	if got := childs(t, db, 0, &comment.GetterOpts{Status: comment.StatusPending}); len(got) != 1 {
+		t.Errorf("Childs() = %v, want pending comment", ids(got))
+	}
+
+	created, err := db.SetStatus(id, comment.StatusApproved)
+	if err != nil || !created {
+		t.Fatalf("SetStatus() = %v, %v, want created", created, err)
+	}
+	if got := childs(t, db, 0, nil); len(got) != 1 || got[0].Status != comment.StatusApproved {
+		t.Errorf("Childs() = %v, want approved comment", got)
+	}
+
+	if _, err := db.SetStatus(id, comment.StatusApproved); !errors.Is(err, errs.ErrDBNotAffected) {
+		t.Errorf("SetStatus() again err = %v, want %v", err, errs.ErrDBNotAffected)
+	}
+	if _, err := db.SetStatus(id+100, comment.StatusRejected); !errors.Is(err, errs.ErrDBNotAffected) {
+		t.Errorf("SetStatus() missing err = %v, want %v", err, errs.ErrDBNotAffected)
+	}
+
+	// Edit sends comment back to moderation, readers already have it.
+	if err := db.EditComment(id, "edited", comment.StatusPending); err != nil {
+		t.Fatalf("EditComment() err = %v", err)
+	}
+	created, err = db.SetStatus(id, comment.StatusApproved)
+	if err != nil || created {
+		t.Errorf("SetStatus() after edit = %v, %v, want not created", created, err)
+	}
+
+	// Comment approved on create is known to readers too.
+	approved := create(t, db, comment.Comment{Message: "approved"})
+	if _, err := db.SetStatus(approved, comment.StatusRejected); err != nil {
+		t.Fatalf("SetStatus() err = %v", err)
+	}
+	created, err = db.SetStatus(approved, comment.StatusApproved)
+	if err != nil || created {
+		t.Errorf("SetStatus() of rejected = %v, %v, want not created", created, err)
+	}
+}
+
+func testHiddenStatus(t *testing.T, db DB) {
+	pending := create(t, db, comment.Comment{
+		Message: "pending", Status: comment.StatusPending,
+	})
+	root := create(t, db, comment.Comment{Message: "root"})
+	reply := create(t, db, comment.Comment{Message: "reply", ParentID: root})
+	// Approved comment under hidden grandparent.
+	grandchild := create(t, db, comment.Comment{Message: "grandchild", ParentID: reply})
+	if _, err := db.SetStatus(root, comment.StatusRejected); err != nil {
+		t.Fatalf("SetStatus() err = %v", err)
+	}
+
+	tests := []struct {
+		name string // description of this test case
+		id   int64
+	}{
+		{name: "pending", id: pending},
+		{name: "rejected", id: root},
+		{name: "reply of rejected", id: reply},
+		{name: "under rejected grandparent", id: grandchild},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Readers don't see comment by any way.
+			if _, err := db.Single(tt.id, false); !errors.Is(err, errs.ErrDBNotFound) {
+				t.Errorf("Single() err = %v, want %v", err, errs.ErrDBNotFound)
+			}
+			if _, err := db.Path(tt.id, false); !errors.Is(err, errs.ErrDBNotFound) {
+				t.Errorf("Path() err = %v, want %v", err, errs.ErrDBNotFound)
+			}
+
+			// Moderators see it.
+			got, err := db.Single(tt.id, true)
+			if err != nil || got.ID != tt.id {
+				t.Errorf("Single() = %v, %v, want %v", got.ID, err, tt.id)
+			}
+			if _, err := db.Path(tt.id, true); err != nil {
+				t.Errorf("Path() err = %v", err)
+			}
+		})
+	}
+
+	for _, id := range []int64{pending, root, reply, grandchild} {
+		_, err := db.Tree(id, &comment.TreeOpts{MaxDepth: 1, Limit: 1})
+		if !errors.Is(err, errs.ErrDBNotFound) {
+			t.Errorf("Tree(%d) err = %v, want %v", id, err, errs.ErrDBNotFound)
+		}
+		_, err = db.Tree(id, &comment.TreeOpts{MaxDepth: 1, Limit: 1, AnyStatus: true})
+		if err != nil {
+			t.Errorf("Tree(%d) with any status err = %v", id, err)
+		}
+
+		if _, err := db.Revisions(id, false); !errors.Is(err, errs.ErrDBNotFound) {
+			t.Errorf("Revisions(%d) err = %v, want %v", id, err, errs.ErrDBNotFound)
+		}
+		if _, err := db.Revisions(id, true); err != nil {
+			t.Errorf("Revisions(%d) with any status err = %v", id, err)
+		}
+	}
+}
+
+func testReactions(t *testing.T, db DB) {
+	id := create(t, db, comment.Comment{Message: "root"})
+	bob, err := db.CreateUser(user.User{Name: "bob", Role: user.RoleUser}, "bob")
+	if err != nil {
+		t.Fatalf("CreateUser() err = %v", err)
+	}
+	alice, err := db.CreateUser(user.User{Name: "alice", Role: user.RoleUser}, "alice")
+	if err != nil {
+		t.Fatalf("CreateUser() err = %v", err)
+	}
+
+	// New vote replaces opposite, the same reaction again isn't error.
+	for _, r := range []struct {
+		userID int64
+		kind   string
+	}{
+		{bob, comment.ReactionUp},
+		{bob, comment.ReactionDown},
+		{alice, comment.ReactionDown},
+		{alice, comment.ReactionDown},
+		{alice, comment.Emojis[0]},
+	} {
+		if err := db.AddReaction(id, r.userID, r.kind); err != nil {
+			t.Fatalf("AddReaction() err = %v", err)
+		}
+	}
+
+	got := parent(t, db, id)
+	if got.Score != -2 || got.Reactions[comment.ReactionDown] != 2 ||
+		got.Reactions[comment.ReactionUp] != 0 || got.Reactions[comment.Emojis[0]] != 1 {
+		t.Errorf("Parent() score = %v, reactions = %v", got.Score, got.Reactions)
+	}
+
+	if err := db.DeleteComment(id, 0, false); err != nil {
+		t.Fatalf("DeleteComment() err = %v", err)
+	}
+	if err := db.AddReaction(id, bob, comment.ReactionUp); !errors.Is(err, errs.ErrDBNotAffected) {
+		t.Errorf("AddReaction() err = %v, want %v", err, errs.ErrDBNotAffected)
+	}
+
+	// Votes on hidden comment would count in top sort after approve.
+	for _, status := range []string{comment.StatusPending, comment.StatusRejected} {
+		hidden := create(t, db, comment.Comment{Message: status, Status: status})
+		err := db.AddReaction(hidden, bob, comment.ReactionUp)
+		if !errors.Is(err, errs.ErrDBNotAffected) {
+			t.Errorf("AddReaction(%s) err = %v, want %v", status, err, errs.ErrDBNotAffected)
+		}
+	}
+}
+
+func testUsers(t *testing.T, db DB) {
+	id, err := db.CreateUser(user.User{Name: "bob", Role: user.RoleModerator}, "hash")
+	if err != nil {
+		t.Fatalf("CreateUser() err = %v", err)
+	}
+
+	got, err := db.UserByToken("hash")
+	if err != nil {
+		t.Fatalf("UserByToken() err = %v", err)
+	}
+	if got.ID != id || got.Name != "bob" || got.Role != user.RoleModerator {
+		t.Errorf("UserByToken() = %+v, want bob", got)
+	}
+
+	_, err = db.CreateUser(user.User{Name: "bob", Role: user.RoleUser}, "other")
+	if !errors.Is(err, errs.ErrDBAlreadyExists) {
+		t.Errorf("CreateUser() err = %v, want %v", err, errs.ErrDBAlreadyExists)
+	}
+	if _, err := db.UserByToken("missing"); !errors.Is(err, errs.ErrDBNotFound) {
+		t.Errorf("UserByToken() err = %v, want %v", err, errs.ErrDBNotFound)
+	}
+}
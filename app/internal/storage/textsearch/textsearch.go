@@ -0,0 +1,203 @@
+package textsearch
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+const (
+	// Default pg_trgm.word_similarity_threshold.
+	SimilarityThreshold = 0.6
+	// Rank of matched message without found words.
+	MinRank = 1e-20
+	// Words in snippet, like default MaxWords of ts_headline.
+	SnippetWords = 35
+
+	// Marks of found words in snippet.
+	StartSel = "<b>"
+	StopSel  = "</b>"
+)
+
+// Frequent english words which are skipped like by english text search.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "for": true, "from": true, "in": true,
+	"is": true, "it": true, "of": true, "on": true, "that": true,
+	"the": true, "this": true, "to": true, "was": true, "with": true,
+}
+
+func words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// stem cuts common english endings, it's rough version of snowball stemmer.
+func stem(w string) string {
+	for _, suffix := range []string{"ing", "ed", "es", "s"} {
+		if cut, ok := strings.CutSuffix(w, suffix); ok && len([]rune(cut)) >= 3 {
+			return cut
+		}
+	}
+
+	return w
+}
+
+type term struct {
+	stem   string
+	negate bool
+}
+
+// parseQuery splits query like websearch_to_tsquery: groups separated
+// by "or" are alternatives, terms of group are required,
+// terms with "-" are excluded. Quotes are ignored.
+func parseQuery(query string) [][]term {
+	var groups [][]term
+	var group []term
+	for _, field := range strings.Fields(strings.ReplaceAll(query, `"`, " ")) {
+		if strings.EqualFold(field, "or") {
+			if len(group) > 0 {
+				groups = append(groups, group)
+			}
+			group = nil
+			continue
+		}
+
+		negate := strings.HasPrefix(field, "-")
+		for _, w := range words(field) {
+			if !stopWords[w] {
+				group = append(group, term{stem: stem(w), negate: negate})
+			}
+		}
+	}
+	if len(group) > 0 {
+		groups = append(groups, group)
+	}
+
+	return groups
+}
+
+// Fulltext returns rank of message for query and message with
+// marked found words, rank is 0 when message doesn't match.
+func Fulltext(query, message string) (float64, string) {
+	msgWords := words(message)
+	counts := make(map[string]int, len(msgWords))
+	for _, w := range msgWords {
+		counts[stem(w)]++
+	}
+
+	matched := false
+	found := make(map[string]bool)
+	for _, group := range parseQuery(query) {
+		ok := true
+		for _, t := range group {
+			if (counts[t.stem] > 0) == t.negate {
+				ok = false
+				break
+			}
+		}
+		if !ok {
+			continue
+		}
+		matched = true
+		for _, t := range group {
+			if !t.negate {
+				found[t.stem] = true
+			}
+		}
+	}
+	if !matched {
+		return 0, ""
+	}
+
+	// Share of found words in message, query of only
+	// excluded words gets the lowest rank.
+	n := 0
+	for s := range found {
+		n += counts[s]
+	}
+	rank := max(float64(n)/float64(max(len(msgWords), 1)), MinRank)
+
+	return rank, snippet(message, found)
+}
+
+// snippet marks found words and cuts message around the first of them.
+func snippet(message string, found map[string]bool) string {
+	fields := strings.Fields(message)
+	first := -1
+	for i, f := range fields {
+		ws := words(f)
+		if len(ws) == 0 || !found[stem(ws[0])] {
+			continue
+		}
+		if first < 0 {
+			first = i
+		}
+		fields[i] = StartSel + f + StopSel
+	}
+
+	start := max(0, min(first-SnippetWords/2, len(fields)-SnippetWords))
+	end := min(len(fields), start+SnippetWords)
+
+	return strings.Join(fields[start:end], " ")
+}
+
+// trigrams returns trigrams of words of s like pg_trgm,
+// every word is padded with two spaces before and one after.
+func trigrams(s string) []string {
+	var res []string
+	for _, w := range words(s) {
+		r := []rune("  " + w + " ")
+		for i := 0; i+3 <= len(r); i++ {
+			res = append(res, string(r[i:i+3]))
+		}
+	}
+
+	return res
+}
+
+// WordSimilarity returns the greatest similarity between trigrams of
+// query and trigrams of continuous extent of message, like pg_trgm.
+func WordSimilarity(query, message string) float64 {
+	set := make(map[string]bool)
+	for _, t := range trigrams(query) {
+		set[t] = true
+	}
+	if len(set) == 0 {
+		return 0
+	}
+
+	// The best extent starts and ends with common trigram.
+	msg := trigrams(message)
+	var common []int
+	for i, t := range msg {
+		if set[t] {
+			common = append(common, i)
+		}
+	}
+
+	best := 0.0
+	for a, i := range common {
+		extent := make(map[string]bool)
+		shared := 0
+		last := i
+		for _, j := range common[a:] {
+			for _, t := range msg[last : j+1] {
+				if extent[t] {
+					continue
+				}
+				extent[t] = true
+				if set[t] {
+					shared++
+				}
+			}
+			last = j + 1
+
+			sim := float64(shared) / float64(len(set)+len(extent)-shared)
+			best = math.Max(best, sim)
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,35 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+
+	"CommentTree/internal/entities/user"
+	"CommentTree/pkg/errs"
+)
+
+func (s *Storage) CreateUser(u user.User, tokenHash string) (int64, error) {
+	const op = "internal.storage.CreateUser"
+
+	id, err := s.db.CreateUser(u, tokenHash)
+	if errors.Is(err, errs.ErrDBAlreadyExists) {
+		return 0, ErrAlreadyExists
+	} else if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (s *Storage) UserByToken(tokenHash string) (user.User, error) {
+	const op = "internal.storage.UserByToken"
+
+	u, err := s.db.UserByToken(tokenHash)
+	if errors.Is(err, errs.ErrDBNotFound) {
+		return user.User{}, ErrNotFound
+	} else if err != nil {
+		return user.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return u, nil
+}
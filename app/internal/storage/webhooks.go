@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+
+	"CommentTree/internal/entities/webhook"
+	"CommentTree/pkg/errs"
+)
+
+func (s *Storage) CreateWebhook(w webhook.Webhook) (webhook.Webhook, error) {
+	const op = "internal.storage.CreateWebhook"
+
+	w, err := s.db.CreateWebhook(w)
+	if err != nil {
+		return webhook.Webhook{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return w, nil
+}
+
+func (s *Storage) Webhooks() ([]webhook.Webhook, error) {
+	const op = "internal.storage.Webhooks"
+
+	hooks, err := s.db.Webhooks()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return hooks, nil
+}
+
+func (s *Storage) DeleteWebhook(id int64) error {
+	const op = "internal.storage.DeleteWebhook"
+
+	err := s.db.DeleteWebhook(id)
+	if errors.Is(err, errs.ErrDBNotAffected) {
+		return ErrNotAffected
+	} else if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
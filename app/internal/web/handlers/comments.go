@@ -9,6 +9,8 @@ import (
 	"CommentTree/internal/entities/comment"
 	"CommentTree/internal/entities/request"
 	"CommentTree/internal/entities/response"
+	"CommentTree/internal/entities/user"
+	"CommentTree/internal/entities/webhook"
 	"CommentTree/internal/service"
 
 	"github.com/wb-go/wbf/ginext"
@@ -19,10 +21,45 @@ const (
 	InternalError = "internal error on service"
 )
 
+// idParam parses ":id" from path, on fail it writes bad request response.
+func idParam(ctx *ginext.Context) (int64, bool) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, response.Error(
+			"id should be numeric",
+		))
+		return 0, false
+	}
+	if id <= 0 {
+		ctx.JSON(http.StatusBadRequest, response.Error(
+			"id shoud be > 0",
+		))
+		return 0, false
+	}
+
+	return id, true
+}
+
 type servicer interface {
 	CreateComment(c comment.Comment) (int64, error)
 	Comments(parentID int64, opts *comment.GetterOpts) (*comment.CommentView, error)
-	DeleteComment(id int64) error
+	Comment(u user.User, id int64) (*comment.SingleView, error)
+	Tree(u user.User, id int64, opts *comment.TreeOpts) (*comment.TreeNode, error)
+	Path(u user.User, id int64) ([]comment.Comment, error)
+	EditComment(u user.User, id int64, message string) error
+	Revisions(u user.User, id int64) ([]comment.Revision, error)
+	DeleteComment(u user.User, id int64, cascade bool) error
+	RestoreComment(u user.User, id int64) error
+	AddReaction(u user.User, id int64, kind string) error
+	RemoveReaction(u user.User, id int64, kind string) error
+	ModerationQueue(u user.User, page int) (*comment.CommentView, error)
+	ApproveComment(u user.User, id int64) error
+	RejectComment(u user.User, id int64) error
+	CreateWebhook(u user.User, w webhook.Webhook) (webhook.Webhook, error)
+	Webhooks(u user.User) ([]webhook.Webhook, error)
+	DeleteWebhook(u user.User, id int64) error
+
+	Register(name string) (user.User, string, error)
 }
 
 func CreateComment(s servicer) ginext.HandlerFunc {
@@ -47,11 +84,20 @@ func CreateComment(s servicer) ginext.HandlerFunc {
 			return
 		}
 
+		// Anonymous comment when there is no user.
+		u, _ := CurrentUser(ctx)
 		id, err := s.CreateComment(comment.Comment{
-			Message:  req.Message,
-			ParentID: req.ParentID,
+			Message:   req.Message,
+			ParentID:  req.ParentID,
+			AuthorID:  u.ID,
+			ThreadKey: req.ThreadKey,
 		})
-		if errors.Is(err, service.ErrWrongData) {
+		if errors.Is(err, service.ErrRejected) {
+			ctx.JSON(http.StatusUnprocessableEntity, response.Error(
+				err.Error(),
+			))
+			return
+		} else if errors.Is(err, service.ErrWrongData) {
 			ctx.JSON(http.StatusServiceUnavailable, response.Error(
 				err.Error(),
 			))
@@ -74,22 +120,21 @@ func DeleteComment(s servicer) ginext.HandlerFunc {
 
 		ctx.Header("Content-Type", "application/json")
 
-		idParam := ctx.Param("id")
-		id, err := strconv.ParseInt(idParam, 10, 64)
-		if err != nil {
-			ctx.JSON(http.StatusBadRequest, response.Error(
-				"id should be numeric",
-			))
+		id, ok := idParam(ctx)
+		if !ok {
 			return
 		}
-		if id <= 0 {
+
+		var req request.DeleteComment
+		if err := ctx.BindQuery(&req); err != nil {
 			ctx.JSON(http.StatusBadRequest, response.Error(
-				"id shoud be > 0",
+				"wrong query, data or types in query",
 			))
 			return
 		}
 
-		err = s.DeleteComment(id)
+		u, _ := CurrentUser(ctx)
+		err := s.DeleteComment(u, id, req.Cascade)
 		if errors.Is(err, service.ErrWrongData) {
 			ctx.JSON(http.StatusServiceUnavailable, response.Error(
 				err.Error(),
@@ -100,6 +145,11 @@ func DeleteComment(s servicer) ginext.HandlerFunc {
 				"can't find comment with this id",
 			))
 			return
+		} else if errors.Is(err, service.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, response.Error(
+				err.Error(),
+			))
+			return
 		} else if err != nil {
 			zlog.Logger.Error().Err(fmt.Errorf("%s: %w", op, err)).Send()
 			ctx.JSON(http.StatusInternalServerError, response.Error(
@@ -134,11 +184,18 @@ func Comments(s servicer) ginext.HandlerFunc {
 			return
 		}
 
+		// Moderators see pending and rejected comments in threads too.
+		u, _ := CurrentUser(ctx)
 		id := req.ParentID
 		opts := &comment.GetterOpts{
+			AnyStatus:    u.IsModerator(),
 			Page:         req.Page,
 			Substr:       req.Substr,
 			SearchGlobal: req.SearchGlobal,
+			ThreadKey:    req.ThreadKey,
+			Sort:         req.Sort,
+			SearchMode:   req.SearchMode,
+			After:        req.Cursor,
 		}
 
 		comms, err := s.Comments(id, opts)
@@ -163,3 +220,262 @@ func Comments(s servicer) ginext.HandlerFunc {
 		ctx.JSON(http.StatusOK, response.Result(comms))
 	}
 }
+
+func Comment(s servicer) ginext.HandlerFunc {
+	return func(ctx *ginext.Context) {
+		const op = "internal.web.handlers.Comment"
+
+		ctx.Header("Content-Type", "application/json")
+
+		id, ok := idParam(ctx)
+		if !ok {
+			return
+		}
+
+		// Moderators see comments before approve.
+		u, _ := CurrentUser(ctx)
+		comm, err := s.Comment(u, id)
+		if errors.Is(err, service.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, response.Error(
+				"not found comment",
+			))
+			return
+		} else if errors.Is(err, service.ErrWrongData) {
+			ctx.JSON(http.StatusServiceUnavailable, response.Error(
+				err.Error(),
+			))
+			return
+		} else if err != nil {
+			zlog.Logger.Error().Err(fmt.Errorf("%s: %w", op, err)).Send()
+			ctx.JSON(http.StatusInternalServerError, response.Error(
+				InternalError,
+			))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, response.Result(comm))
+	}
+}
+
+func Tree(s servicer) ginext.HandlerFunc {
+	return func(ctx *ginext.Context) {
+		const op = "internal.web.handlers.Tree"
+
+		ctx.Header("Content-Type", "application/json")
+
+		id, ok := idParam(ctx)
+		if !ok {
+			return
+		}
+
+		var req request.GetTree
+		if err := ctx.BindQuery(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, response.Error(
+				"wrong query, data or types in query",
+			))
+			return
+		}
+
+		if msg := req.Validate(); msg != "" {
+			ctx.JSON(http.StatusBadRequest, response.Error(
+				msg,
+			))
+			return
+		}
+
+		u, _ := CurrentUser(ctx)
+		tree, err := s.Tree(u, id, &comment.TreeOpts{
+			MaxDepth: req.MaxDepth,
+			Limit:    req.Limit,
+		})
+		if errors.Is(err, service.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, response.Error(
+				"not found comment",
+			))
+			return
+		} else if errors.Is(err, service.ErrWrongData) {
+			ctx.JSON(http.StatusServiceUnavailable, response.Error(
+				err.Error(),
+			))
+			return
+		} else if err != nil {
+			zlog.Logger.Error().Err(fmt.Errorf("%s: %w", op, err)).Send()
+			ctx.JSON(http.StatusInternalServerError, response.Error(
+				InternalError,
+			))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, response.Result(tree))
+	}
+}
+
+func Path(s servicer) ginext.HandlerFunc {
+	return func(ctx *ginext.Context) {
+		const op = "internal.web.handlers.Path"
+
+		ctx.Header("Content-Type", "application/json")
+
+		id, ok := idParam(ctx)
+		if !ok {
+			return
+		}
+
+		u, _ := CurrentUser(ctx)
+		path, err := s.Path(u, id)
+		if errors.Is(err, service.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, response.Error(
+				"not found comment",
+			))
+			return
+		} else if errors.Is(err, service.ErrWrongData) {
+			ctx.JSON(http.StatusServiceUnavailable, response.Error(
+				err.Error(),
+			))
+			return
+		} else if err != nil {
+			zlog.Logger.Error().Err(fmt.Errorf("%s: %w", op, err)).Send()
+			ctx.JSON(http.StatusInternalServerError, response.Error(
+				InternalError,
+			))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, response.Result(path))
+	}
+}
+
+func RestoreComment(s servicer) ginext.HandlerFunc {
+	return func(ctx *ginext.Context) {
+		const op = "internal.web.handlers.RestoreComment"
+
+		ctx.Header("Content-Type", "application/json")
+
+		id, ok := idParam(ctx)
+		if !ok {
+			return
+		}
+
+		u, _ := CurrentUser(ctx)
+		err := s.RestoreComment(u, id)
+		if errors.Is(err, service.ErrWrongData) {
+			ctx.JSON(http.StatusServiceUnavailable, response.Error(
+				err.Error(),
+			))
+			return
+		} else if errors.Is(err, service.ErrNotAffected) {
+			ctx.JSON(http.StatusServiceUnavailable, response.Error(
+				"can't find deleted comment with this id",
+			))
+			return
+		} else if errors.Is(err, service.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, response.Error(
+				err.Error(),
+			))
+			return
+		} else if err != nil {
+			zlog.Logger.Error().Err(fmt.Errorf("%s: %w", op, err)).Send()
+			ctx.JSON(http.StatusInternalServerError, response.Error(
+				InternalError,
+			))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, response.OK())
+	}
+}
+
+func EditComment(s servicer) ginext.HandlerFunc {
+	return func(ctx *ginext.Context) {
+		const op = "internal.web.handlers.EditComment"
+
+		ctx.Header("Content-Type", "application/json")
+
+		id, ok := idParam(ctx)
+		if !ok {
+			return
+		}
+
+		var req request.EditComment
+		if err := ctx.BindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, response.Error(
+				"wrong json, data or types in json",
+			))
+			return
+		}
+
+		if msg := req.Validate(); msg != "" {
+			ctx.JSON(http.StatusBadRequest, response.Error(
+				msg,
+			))
+			return
+		}
+
+		u, _ := CurrentUser(ctx)
+		err := s.EditComment(u, id, req.Message)
+		if errors.Is(err, service.ErrRejected) {
+			ctx.JSON(http.StatusUnprocessableEntity, response.Error(
+				err.Error(),
+			))
+			return
+		} else if errors.Is(err, service.ErrWrongData) {
+			ctx.JSON(http.StatusServiceUnavailable, response.Error(
+				err.Error(),
+			))
+			return
+		} else if errors.Is(err, service.ErrNotAffected) {
+			ctx.JSON(http.StatusServiceUnavailable, response.Error(
+				"can't find comment with this id",
+			))
+			return
+		} else if errors.Is(err, service.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, response.Error(
+				err.Error(),
+			))
+			return
+		} else if err != nil {
+			zlog.Logger.Error().Err(fmt.Errorf("%s: %w", op, err)).Send()
+			ctx.JSON(http.StatusInternalServerError, response.Error(
+				InternalError,
+			))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, response.OK())
+	}
+}
+
+func Revisions(s servicer) ginext.HandlerFunc {
+	return func(ctx *ginext.Context) {
+		const op = "internal.web.handlers.Revisions"
+
+		ctx.Header("Content-Type", "application/json")
+
+		id, ok := idParam(ctx)
+		if !ok {
+			return
+		}
+
+		u, _ := CurrentUser(ctx)
+		revs, err := s.Revisions(u, id)
+		if errors.Is(err, service.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, response.Error(
+				"not found comment",
+			))
+			return
+		} else if errors.Is(err, service.ErrWrongData) {
+			ctx.JSON(http.StatusServiceUnavailable, response.Error(
+				err.Error(),
+			))
+			return
+		} else if err != nil {
+			zlog.Logger.Error().Err(fmt.Errorf("%s: %w", op, err)).Send()
+			ctx.JSON(http.StatusInternalServerError, response.Error(
+				InternalError,
+			))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, response.Result(revs))
+	}
+}
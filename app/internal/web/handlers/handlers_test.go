@@ -10,6 +10,8 @@ import (
 
 	"CommentTree/internal/entities/comment"
 	"CommentTree/internal/entities/request"
+	"CommentTree/internal/entities/user"
+	"CommentTree/internal/entities/webhook"
 	"CommentTree/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -18,7 +20,21 @@ import (
 type ServiceMock struct {
 	createF func(c comment.Comment) (int64, error)
 	getF    func(parentID int64, opts *comment.GetterOpts) (*comment.CommentView, error)
-	deleteF func(id int64) error
+	deleteF func(u user.User, id int64, cascade bool) error
+	restF   func(u user.User, id int64) error
+	editF   func(u user.User, id int64, message string) error
+	regF    func(name string) (user.User, string, error)
+	revsF   func(id int64) ([]comment.Revision, error)
+	oneF    func(id int64) (*comment.SingleView, error)
+	treeF   func(id int64, opts *comment.TreeOpts) (*comment.TreeNode, error)
+	pathF   func(id int64) ([]comment.Comment, error)
+	reactF  func(u user.User, id int64, kind string) error
+	unreact func(u user.User, id int64, kind string) error
+	queueF  func(u user.User, page int) (*comment.CommentView, error)
+	statusF func(u user.User, id int64) error
+	hookF   func(u user.User, w webhook.Webhook) (webhook.Webhook, error)
+	hooksF  func(u user.User) ([]webhook.Webhook, error)
+	unhookF func(u user.User, id int64) error
 }
 
 func (sm *ServiceMock) CreateComment(c comment.Comment) (int64, error) {
@@ -29,8 +45,68 @@ func (sm *ServiceMock) Comments(parentID int64, opts *comment.GetterOpts) (*comm
 	return sm.getF(parentID, opts)
 }
 
-func (sm *ServiceMock) DeleteComment(id int64) error {
-	return sm.deleteF(id)
+func (sm *ServiceMock) DeleteComment(u user.User, id int64, cascade bool) error {
+	return sm.deleteF(u, id, cascade)
+}
+
+func (sm *ServiceMock) RestoreComment(u user.User, id int64) error {
+	return sm.restF(u, id)
+}
+
+func (sm *ServiceMock) EditComment(u user.User, id int64, message string) error {
+	return sm.editF(u, id, message)
+}
+
+func (sm *ServiceMock) Register(name string) (user.User, string, error) {
+	return sm.regF(name)
+}
+
+func (sm *ServiceMock) Revisions(u user.User, id int64) ([]comment.Revision, error) {
+	return sm.revsF(id)
+}
+
+func (sm *ServiceMock) Comment(u user.User, id int64) (*comment.SingleView, error) {
+	return sm.oneF(id)
+}
+
+func (sm *ServiceMock) Tree(u user.User, id int64, opts *comment.TreeOpts) (*comment.TreeNode, error) {
+	return sm.treeF(id, opts)
+}
+
+func (sm *ServiceMock) Path(u user.User, id int64) ([]comment.Comment, error) {
+	return sm.pathF(id)
+}
+
+func (sm *ServiceMock) AddReaction(u user.User, id int64, kind string) error {
+	return sm.reactF(u, id, kind)
+}
+
+func (sm *ServiceMock) RemoveReaction(u user.User, id int64, kind string) error {
+	return sm.unreact(u, id, kind)
+}
+
+func (sm *ServiceMock) ModerationQueue(u user.User, page int) (*comment.CommentView, error) {
+	return sm.queueF(u, page)
+}
+
+func (sm *ServiceMock) ApproveComment(u user.User, id int64) error {
+	return sm.statusF(u, id)
+}
+
+func (sm *ServiceMock) RejectComment(u user.User, id int64) error {
+	return sm.statusF(u, id)
+}
+
+func (sm *ServiceMock) CreateWebhook(u user.User, w webhook.Webhook) (webhook.Webhook, error) {
+	return sm.hookF(u, w)
+}
+
+func (sm *ServiceMock) Webhooks(u user.User) ([]webhook.Webhook, error) {
+	return sm.hooksF(u)
+}
+
+func (sm *ServiceMock) DeleteWebhook(u user.User, id int64) error {
+	return sm.unhookF(u, id)
 }
 
 func TestCreateComment(t *testing.T) {
@@ -91,6 +167,19 @@ func TestCreateComment(t *testing.T) {
 			},
 			want: http.StatusServiceUnavailable,
 		},
+		{
+			name: "service err: rejected by filter",
+			s: &ServiceMock{
+				createF: func(c comment.Comment) (int64, error) {
+					return 0, service.ErrRejected
+				},
+			},
+			body: request.CreateComment{
+				Message:  "hihi",
+				ParentID: 12,
+			},
+			want: http.StatusUnprocessableEntity,
+		},
 		{
 			name: "service err: unknown err",
 			s: &ServiceMock{
@@ -152,17 +241,40 @@ func TestDeleteComment(t *testing.T) {
 		{
 			name: "good",
 			s: &ServiceMock{
-				deleteF: func(id int64) error {
+				deleteF: func(u user.User, id int64, cascade bool) error {
 					return nil
 				},
 			},
 			param: "12",
 			want:  http.StatusOK,
 		},
+		{
+			name: "good cascade",
+			s: &ServiceMock{
+				deleteF: func(u user.User, id int64, cascade bool) error {
+					if !cascade {
+						return errors.New("cascade not passed")
+					}
+					return nil
+				},
+			},
+			param: "12?cascade=true",
+			want:  http.StatusOK,
+		},
+		{
+			name: "bad cascade",
+			s: &ServiceMock{
+				deleteF: func(u user.User, id int64, cascade bool) error {
+					return nil
+				},
+			},
+			param: "12?cascade=asdf",
+			want:  http.StatusBadRequest,
+		},
 		{
 			name: "bad json",
 			s: &ServiceMock{
-				deleteF: func(id int64) error {
+				deleteF: func(u user.User, id int64, cascade bool) error {
 					return nil
 				},
 			},
@@ -172,7 +284,7 @@ func TestDeleteComment(t *testing.T) {
 		{
 			name: "not valid data in json",
 			s: &ServiceMock{
-				deleteF: func(id int64) error {
+				deleteF: func(u user.User, id int64, cascade bool) error {
 					return nil
 				},
 			},
@@ -182,7 +294,7 @@ func TestDeleteComment(t *testing.T) {
 		{
 			name: "not valid id",
 			s: &ServiceMock{
-				deleteF: func(id int64) error {
+				deleteF: func(u user.User, id int64, cascade bool) error {
 					return service.ErrWrongData
 				},
 			},
@@ -192,17 +304,27 @@ func TestDeleteComment(t *testing.T) {
 		{
 			name: "not affected",
 			s: &ServiceMock{
-				deleteF: func(id int64) error {
+				deleteF: func(u user.User, id int64, cascade bool) error {
 					return service.ErrNotAffected
 				},
 			},
 			param: "1",
 			want:  http.StatusServiceUnavailable,
 		},
+		{
+			name: "not author",
+			s: &ServiceMock{
+				deleteF: func(u user.User, id int64, cascade bool) error {
+					return service.ErrForbidden
+				},
+			},
+			param: "1",
+			want:  http.StatusForbidden,
+		},
 		{
 			name: "unknown err",
 			s: &ServiceMock{
-				deleteF: func(id int64) error {
+				deleteF: func(u user.User, id int64, cascade bool) error {
 					return errors.New("unknown")
 				},
 			},
@@ -273,6 +395,16 @@ func TestGetComments(t *testing.T) {
 			query: "?parent=-1",
 			want:  http.StatusBadRequest,
 		},
+		{
+			name: "bad cursor",
+			s: &ServiceMock{
+				getF: func(parentID int64, opts *comment.GetterOpts) (*comment.CommentView, error) {
+					return nil, nil
+				},
+			},
+			query: "?parent=12&after=asdf",
+			want:  http.StatusBadRequest,
+		},
 		{
 			name: "not valid data in service",
 			s: &ServiceMock{
@@ -329,3 +461,519 @@ func TestGetComments(t *testing.T) {
 		})
 	}
 }
+
+func TestGetComment(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		s     servicer
+		param string
+		want  int
+	}{
+		{
+			name: "good",
+			s: &ServiceMock{
+				oneF: func(id int64) (*comment.SingleView, error) {
+					return &comment.SingleView{}, nil
+				},
+			},
+			param: "12",
+			want:  http.StatusOK,
+		},
+		{
+			name: "bad param",
+			s: &ServiceMock{
+				oneF: func(id int64) (*comment.SingleView, error) {
+					return nil, nil
+				},
+			},
+			param: "asdf",
+			want:  http.StatusBadRequest,
+		},
+		{
+			name: "not valid id",
+			s: &ServiceMock{
+				oneF: func(id int64) (*comment.SingleView, error) {
+					return nil, nil
+				},
+			},
+			param: "-1",
+			want:  http.StatusBadRequest,
+		},
+		{
+			name: "not found in service",
+			s: &ServiceMock{
+				oneF: func(id int64) (*comment.SingleView, error) {
+					return nil, service.ErrNotFound
+				},
+			},
+			param: "12",
+			want:  http.StatusNotFound,
+		},
+		{
+			name: "unknown err",
+			s: &ServiceMock{
+				oneF: func(id int64) (*comment.SingleView, error) {
+					return nil, errors.New("unknown")
+				},
+			},
+			param: "12",
+			want:  http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := "/endpoint/"
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET(url+":id", Comment(tt.s))
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(
+				http.MethodGet, url+tt.param, nil,
+			)
+
+			router.ServeHTTP(rr, req)
+
+			if rr.Result().StatusCode != tt.want {
+				t.Errorf(
+					"handler Comment() = %v, want %v",
+					rr.Result().StatusCode, tt.want,
+				)
+			}
+		})
+	}
+}
+
+func TestTree(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		s     servicer
+		param string
+		query string
+		want  int
+	}{
+		{
+			name: "good",
+			s: &ServiceMock{
+				treeF: func(id int64, opts *comment.TreeOpts) (*comment.TreeNode, error) {
+					return &comment.TreeNode{}, nil
+				},
+			},
+			param: "12",
+			query: "?max_depth=2&limit=5",
+			want:  http.StatusOK,
+		},
+		{
+			name: "bad param",
+			s: &ServiceMock{
+				treeF: func(id int64, opts *comment.TreeOpts) (*comment.TreeNode, error) {
+					return nil, nil
+				},
+			},
+			param: "asdf",
+			want:  http.StatusBadRequest,
+		},
+		{
+			name: "bad query",
+			s: &ServiceMock{
+				treeF: func(id int64, opts *comment.TreeOpts) (*comment.TreeNode, error) {
+					return nil, nil
+				},
+			},
+			param: "12",
+			query: "?max_depth=asdf",
+			want:  http.StatusBadRequest,
+		},
+		{
+			name: "too deep",
+			s: &ServiceMock{
+				treeF: func(id int64, opts *comment.TreeOpts) (*comment.TreeNode, error) {
+					return nil, nil
+				},
+			},
+			param: "12",
+			query: "?max_depth=1000",
+			want:  http.StatusBadRequest,
+		},
+		{
+			name: "not found in service",
+			s: &ServiceMock{
+				treeF: func(id int64, opts *comment.TreeOpts) (*comment.TreeNode, error) {
+					return nil, service.ErrNotFound
+				},
+			},
+			param: "12",
+			want:  http.StatusNotFound,
+		},
+		{
+			name: "unknown err",
+			s: &ServiceMock{
+				treeF: func(id int64, opts *comment.TreeOpts) (*comment.TreeNode, error) {
+					return nil, errors.New("unknown")
+				},
+			},
+			param: "12",
+			want:  http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := "/endpoint/"
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET(url+":id/tree", Tree(tt.s))
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(
+				http.MethodGet, url+tt.param+"/tree"+tt.query, nil,
+			)
+
+			router.ServeHTTP(rr, req)
+
+			if rr.Result().StatusCode != tt.want {
+				t.Errorf(
+					"handler Tree() = %v, want %v",
+					rr.Result().StatusCode, tt.want,
+				)
+			}
+		})
+	}
+}
+
+func TestPath(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		s     servicer
+		param string
+		want  int
+	}{
+		{
+			name: "good",
+			s: &ServiceMock{
+				pathF: func(id int64) ([]comment.Comment, error) {
+					return []comment.Comment{{ID: 1}, {ID: 12, ParentID: 1}}, nil
+				},
+			},
+			param: "12",
+			want:  http.StatusOK,
+		},
+		{
+			name: "bad param",
+			s: &ServiceMock{
+				pathF: func(id int64) ([]comment.Comment, error) {
+					return nil, nil
+				},
+			},
+			param: "asdf",
+			want:  http.StatusBadRequest,
+		},
+		{
+			name: "not found in service",
+			s: &ServiceMock{
+				pathF: func(id int64) ([]comment.Comment, error) {
+					return nil, service.ErrNotFound
+				},
+			},
+			param: "12",
+			want:  http.StatusNotFound,
+		},
+		{
+			name: "unknown err",
+			s: &ServiceMock{
+				pathF: func(id int64) ([]comment.Comment, error) {
+					return nil, errors.New("unknown")
+				},
+			},
+			param: "12",
+			want:  http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := "/endpoint/"
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET(url+":id/path", Path(tt.s))
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(
+				http.MethodGet, url+tt.param+"/path", nil,
+			)
+
+			router.ServeHTTP(rr, req)
+
+			if rr.Result().StatusCode != tt.want {
+				t.Errorf(
+					"handler Path() = %v, want %v",
+					rr.Result().StatusCode, tt.want,
+				)
+			}
+		})
+	}
+}
+
+func TestRestoreComment(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		s     servicer
+		param string
+		want  int
+	}{
+		{
+			name: "good",
+			s: &ServiceMock{
+				restF: func(u user.User, id int64) error {
+					return nil
+				},
+			},
+			param: "12",
+			want:  http.StatusOK,
+		},
+		{
+			name: "bad param",
+			s: &ServiceMock{
+				restF: func(u user.User, id int64) error {
+					return nil
+				},
+			},
+			param: "asdf",
+			want:  http.StatusBadRequest,
+		},
+		{
+			name: "not affected",
+			s: &ServiceMock{
+				restF: func(u user.User, id int64) error {
+					return service.ErrNotAffected
+				},
+			},
+			param: "12",
+			want:  http.StatusServiceUnavailable,
+		},
+		{
+			name: "unknown err",
+			s: &ServiceMock{
+				restF: func(u user.User, id int64) error {
+					return errors.New("unknown")
+				},
+			},
+			param: "12",
+			want:  http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := "/endpoint/"
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.POST(url+":id/restore", RestoreComment(tt.s))
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(
+				http.MethodPost, url+tt.param+"/restore", nil,
+			)
+
+			router.ServeHTTP(rr, req)
+
+			if rr.Result().StatusCode != tt.want {
+				t.Errorf(
+					"handler RestoreComment() = %v, want %v",
+					rr.Result().StatusCode, tt.want,
+				)
+			}
+		})
+	}
+}
+
+func TestEditComment(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		s     servicer
+		param string
+		body  string
+		want  int
+	}{
+		{
+			name: "good",
+			s: &ServiceMock{
+				editF: func(u user.User, id int64, message string) error {
+					return nil
+				},
+			},
+			param: "12",
+			body:  `{"message": "new"}`,
+			want:  http.StatusOK,
+		},
+		{
+			name: "bad param",
+			s: &ServiceMock{
+				editF: func(u user.User, id int64, message string) error {
+					return nil
+				},
+			},
+			param: "asdf",
+			body:  `{"message": "new"}`,
+			want:  http.StatusBadRequest,
+		},
+		{
+			name: "bad json",
+			s: &ServiceMock{
+				editF: func(u user.User, id int64, message string) error {
+					return nil
+				},
+			},
+			param: "12",
+			body:  `{"}`,
+			want:  http.StatusBadRequest,
+		},
+		{
+			name: "empty message",
+			s: &ServiceMock{
+				editF: func(u user.User, id int64, message string) error {
+					return nil
+				},
+			},
+			param: "12",
+			body:  `{"message": ""}`,
+			want:  http.StatusBadRequest,
+		},
+		{
+			name: "not affected",
+			s: &ServiceMock{
+				editF: func(u user.User, id int64, message string) error {
+					return service.ErrNotAffected
+				},
+			},
+			param: "12",
+			body:  `{"message": "new"}`,
+			want:  http.StatusServiceUnavailable,
+		},
+		{
+			name: "not author",
+			s: &ServiceMock{
+				editF: func(u user.User, id int64, message string) error {
+					return service.ErrForbidden
+				},
+			},
+			param: "12",
+			body:  `{"message": "new"}`,
+			want:  http.StatusForbidden,
+		},
+		{
+			name: "unknown err",
+			s: &ServiceMock{
+				editF: func(u user.User, id int64, message string) error {
+					return errors.New("unknown")
+				},
+			},
+			param: "12",
+			body:  `{"message": "new"}`,
+			want:  http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := "/endpoint/"
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.PATCH(url+":id", EditComment(tt.s))
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(
+				http.MethodPatch, url+tt.param, bytes.NewReader([]byte(tt.body)),
+			)
+
+			router.ServeHTTP(rr, req)
+
+			if rr.Result().StatusCode != tt.want {
+				t.Errorf(
+					"handler EditComment() = %v, want %v",
+					rr.Result().StatusCode, tt.want,
+				)
+			}
+		})
+	}
+}
+
+func TestRevisions(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		s     servicer
+		param string
+		want  int
+	}{
+		{
+			name: "good",
+			s: &ServiceMock{
+				revsF: func(id int64) ([]comment.Revision, error) {
+					return []comment.Revision{{ID: 1, CommentID: id}}, nil
+				},
+			},
+			param: "12",
+			want:  http.StatusOK,
+		},
+		{
+			name: "bad param",
+			s: &ServiceMock{
+				revsF: func(id int64) ([]comment.Revision, error) {
+					return nil, nil
+				},
+			},
+			param: "0",
+			want:  http.StatusBadRequest,
+		},
+		{
+			name: "not found in service",
+			s: &ServiceMock{
+				revsF: func(id int64) ([]comment.Revision, error) {
+					return nil, service.ErrNotFound
+				},
+			},
+			param: "12",
+			want:  http.StatusNotFound,
+		},
+		{
+			name: "unknown err",
+			s: &ServiceMock{
+				revsF: func(id int64) ([]comment.Revision, error) {
+					return nil, errors.New("unknown")
+				},
+			},
+			param: "12",
+			want:  http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := "/endpoint/"
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET(url+":id/revisions", Revisions(tt.s))
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(
+				http.MethodGet, url+tt.param+"/revisions", nil,
+			)
+
+			router.ServeHTTP(rr, req)
+
+			if rr.Result().StatusCode != tt.want {
+				t.Errorf(
+					"handler Revisions() = %v, want %v",
+					rr.Result().StatusCode, tt.want,
+				)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"CommentTree/internal/entities/request"
+	"CommentTree/internal/entities/response"
+	"CommentTree/internal/entities/user"
+	"CommentTree/internal/service"
+
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+)
+
+func ModerationQueue(s servicer) ginext.HandlerFunc {
+	return func(ctx *ginext.Context) {
+		const op = "internal.web.handlers.ModerationQueue"
+
+		ctx.Header("Content-Type", "application/json")
+
+		var req request.ModerationQueue
+		if err := ctx.BindQuery(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, response.Error(
+				"wrong query, data or types in query",
+			))
+			return
+		}
+
+		if msg := req.Validate(); msg != "" {
+			ctx.JSON(http.StatusBadRequest, response.Error(
+				msg,
+			))
+			return
+		}
+
+		u, _ := CurrentUser(ctx)
+		queue, err := s.ModerationQueue(u, req.Page)
+		if errors.Is(err, service.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, response.Error(
+				err.Error(),
+			))
+			return
+		} else if errors.Is(err, service.ErrWrongData) {
+			ctx.JSON(http.StatusServiceUnavailable, response.Error(
+				err.Error(),
+			))
+			return
+		} else if err != nil {
+			zlog.Logger.Error().Err(fmt.Errorf("%s: %w", op, err)).Send()
+			ctx.JSON(http.StatusInternalServerError, response.Error(
+				InternalError,
+			))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, response.Result(queue))
+	}
+}
+
+func ApproveComment(s servicer) ginext.HandlerFunc {
+	return moderate(s.ApproveComment, "internal.web.handlers.ApproveComment")
+}
+
+func RejectComment(s servicer) ginext.HandlerFunc {
+	return moderate(s.RejectComment, "internal.web.handlers.RejectComment")
+}
+
+// moderate handles action of moderator on entity with id from path.
+func moderate(action func(u user.User, id int64) error, op string) ginext.HandlerFunc {
+	return func(ctx *ginext.Context) {
+		ctx.Header("Content-Type", "application/json")
+
+		id, ok := idParam(ctx)
+		if !ok {
+			return
+		}
+
+		u, _ := CurrentUser(ctx)
+		err := action(u, id)
+		if errors.Is(err, service.ErrWrongData) {
+			ctx.JSON(http.StatusServiceUnavailable, response.Error(
+				err.Error(),
+			))
+			return
+		} else if errors.Is(err, service.ErrNotAffected) {
+			ctx.JSON(http.StatusServiceUnavailable, response.Error(
+				err.Error(),
+			))
+			return
+		} else if errors.Is(err, service.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, response.Error(
+				err.Error(),
+			))
+			return
+		} else if err != nil {
+			zlog.Logger.Error().Err(fmt.Errorf("%s: %w", op, err)).Send()
+			ctx.JSON(http.StatusInternalServerError, response.Error(
+				InternalError,
+			))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, response.OK())
+	}
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"CommentTree/internal/entities/comment"
+	"CommentTree/internal/entities/user"
+	"CommentTree/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestModerationQueue(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		s     servicer
+		query string
+		want  int
+	}{
+		{
+			name: "good",
+			s: &ServiceMock{
+				queueF: func(u user.User, page int) (*comment.CommentView, error) {
+					return &comment.CommentView{}, nil
+				},
+			},
+			query: "?page=2",
+			want:  http.StatusOK,
+		},
+		{
+			name: "bad page",
+			s: &ServiceMock{
+				queueF: func(u user.User, page int) (*comment.CommentView, error) {
+					return &comment.CommentView{}, nil
+				},
+			},
+			query: "?page=-1",
+			want:  http.StatusBadRequest,
+		},
+		{
+			name: "forbidden",
+			s: &ServiceMock{
+				queueF: func(u user.User, page int) (*comment.CommentView, error) {
+					return nil, service.ErrForbidden
+				},
+			},
+			want: http.StatusForbidden,
+		},
+		{
+			name: "unknown err",
+			s: &ServiceMock{
+				queueF: func(u user.User, page int) (*comment.CommentView, error) {
+					return nil, errors.New("unknown")
+				},
+			},
+			want: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := "/endpoint"
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET(url, ModerationQueue(tt.s))
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, url+tt.query, nil)
+
+			router.ServeHTTP(rr, req)
+
+			if rr.Result().StatusCode != tt.want {
+				t.Errorf(
+					"handler ModerationQueue() = %v, want %v",
+					rr.Result().StatusCode, tt.want,
+				)
+			}
+		})
+	}
+}
+
+func TestApproveComment(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		s     servicer
+		param string
+		want  int
+	}{
+		{
+			name: "good",
+			s: &ServiceMock{
+				statusF: func(u user.User, id int64) error {
+					return nil
+				},
+			},
+			param: "12",
+			want:  http.StatusOK,
+		},
+		{
+			name: "bad param",
+			s: &ServiceMock{
+				statusF: func(u user.User, id int64) error {
+					return nil
+				},
+			},
+			param: "asdf",
+			want:  http.StatusBadRequest,
+		},
+		{
+			name: "not affected",
+			s: &ServiceMock{
+				statusF: func(u user.User, id int64) error {
+					return service.ErrNotAffected
+				},
+			},
+			param: "12",
+			want:  http.StatusServiceUnavailable,
+		},
+		{
+			name: "forbidden",
+			s: &ServiceMock{
+				statusF: func(u user.User, id int64) error {
+					return service.ErrForbidden
+				},
+			},
+			param: "12",
+			want:  http.StatusForbidden,
+		},
+		{
+			name: "unknown err",
+			s: &ServiceMock{
+				statusF: func(u user.User, id int64) error {
+					return errors.New("unknown")
+				},
+			},
+			param: "12",
+			want:  http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := "/endpoint/"
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.POST(url+":id/approve", ApproveComment(tt.s))
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(
+				http.MethodPost, url+tt.param+"/approve", nil,
+			)
+
+			router.ServeHTTP(rr, req)
+
+			if rr.Result().StatusCode != tt.want {
+				t.Errorf(
+					"handler ApproveComment() = %v, want %v",
+					rr.Result().StatusCode, tt.want,
+				)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"CommentTree/internal/entities/request"
+	"CommentTree/internal/entities/response"
+	"CommentTree/internal/service"
+
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+)
+
+func AddReaction(s servicer) ginext.HandlerFunc {
+	return func(ctx *ginext.Context) {
+		const op = "internal.web.handlers.AddReaction"
+
+		ctx.Header("Content-Type", "application/json")
+
+		id, ok := idParam(ctx)
+		if !ok {
+			return
+		}
+
+		var req request.Reaction
+		if err := ctx.BindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, response.Error(
+				"wrong json, data or types in json",
+			))
+			return
+		}
+
+		if msg := req.Validate(); msg != "" {
+			ctx.JSON(http.StatusBadRequest, response.Error(
+				msg,
+			))
+			return
+		}
+
+		u, _ := CurrentUser(ctx)
+		err := s.AddReaction(u, id, req.Kind)
+		if errors.Is(err, service.ErrWrongData) {
+			ctx.JSON(http.StatusServiceUnavailable, response.Error(
+				err.Error(),
+			))
+			return
+		} else if errors.Is(err, service.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, response.Error(
+				"not found comment",
+			))
+			return
+		} else if errors.Is(err, service.ErrUnauthorized) {
+			ctx.JSON(http.StatusUnauthorized, response.Error(
+				err.Error(),
+			))
+			return
+		} else if err != nil {
+			zlog.Logger.Error().Err(fmt.Errorf("%s: %w", op, err)).Send()
+			ctx.JSON(http.StatusInternalServerError, response.Error(
+				InternalError,
+			))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, response.OK())
+	}
+}
+
+func RemoveReaction(s servicer) ginext.HandlerFunc {
+	return func(ctx *ginext.Context) {
+		const op = "internal.web.handlers.RemoveReaction"
+
+		ctx.Header("Content-Type", "application/json")
+
+		id, ok := idParam(ctx)
+		if !ok {
+			return
+		}
+
+		var req request.Reaction
+		if err := ctx.BindQuery(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, response.Error(
+				"wrong query, data or types in query",
+			))
+			return
+		}
+
+		if msg := req.Validate(); msg != "" {
+			ctx.JSON(http.StatusBadRequest, response.Error(
+				msg,
+			))
+			return
+		}
+
+		u, _ := CurrentUser(ctx)
+		err := s.RemoveReaction(u, id, req.Kind)
+		if errors.Is(err, service.ErrWrongData) {
+			ctx.JSON(http.StatusServiceUnavailable, response.Error(
+				err.Error(),
+			))
+			return
+		} else if errors.Is(err, service.ErrNotAffected) {
+			ctx.JSON(http.StatusServiceUnavailable, response.Error(
+				"can't find reaction on this comment",
+			))
+			return
+		} else if errors.Is(err, service.ErrUnauthorized) {
+			ctx.JSON(http.StatusUnauthorized, response.Error(
+				err.Error(),
+			))
+			return
+		} else if err != nil {
+			zlog.Logger.Error().Err(fmt.Errorf("%s: %w", op, err)).Send()
+			ctx.JSON(http.StatusInternalServerError, response.Error(
+				InternalError,
+			))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, response.OK())
+	}
+}
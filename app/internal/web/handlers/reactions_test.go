@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"CommentTree/internal/entities/user"
+	"CommentTree/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAddReaction(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		s     servicer
+		param string
+		body  string
+		want  int
+	}{
+		{
+			name: "good",
+			s: &ServiceMock{
+				reactF: func(u user.User, id int64, kind string) error {
+					return nil
+				},
+			},
+			param: "12",
+			body:  `{"kind": "up"}`,
+			want:  http.StatusOK,
+		},
+		{
+			name: "bad param",
+			s: &ServiceMock{
+				reactF: func(u user.User, id int64, kind string) error {
+					return nil
+				},
+			},
+			param: "asdf",
+			body:  `{"kind": "up"}`,
+			want:  http.StatusBadRequest,
+		},
+		{
+			name: "bad json",
+			s: &ServiceMock{
+				reactF: func(u user.User, id int64, kind string) error {
+					return nil
+				},
+			},
+			param: "12",
+			body:  `{"}`,
+			want:  http.StatusBadRequest,
+		},
+		{
+			name: "unknown kind",
+			s: &ServiceMock{
+				reactF: func(u user.User, id int64, kind string) error {
+					return nil
+				},
+			},
+			param: "12",
+			body:  `{"kind": "like"}`,
+			want:  http.StatusBadRequest,
+		},
+		{
+			name: "not found",
+			s: &ServiceMock{
+				reactF: func(u user.User, id int64, kind string) error {
+					return service.ErrNotFound
+				},
+			},
+			param: "12",
+			body:  `{"kind": "down"}`,
+			want:  http.StatusNotFound,
+		},
+		{
+			name: "unknown err",
+			s: &ServiceMock{
+				reactF: func(u user.User, id int64, kind string) error {
+					return errors.New("unknown")
+				},
+			},
+			param: "12",
+			body:  `{"kind": "up"}`,
+			want:  http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := "/endpoint/"
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.POST(url+":id/reactions", AddReaction(tt.s))
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(
+				http.MethodPost, url+tt.param+"/reactions",
+				bytes.NewBufferString(tt.body),
+			)
+
+			router.ServeHTTP(rr, req)
+
+			if rr.Result().StatusCode != tt.want {
+				t.Errorf(
+					"handler AddReaction() = %v, want %v",
+					rr.Result().StatusCode, tt.want,
+				)
+			}
+		})
+	}
+}
+
+func TestRemoveReaction(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		s     servicer
+		param string
+		query string
+		want  int
+	}{
+		{
+			name: "good",
+			s: &ServiceMock{
+				unreact: func(u user.User, id int64, kind string) error {
+					return nil
+				},
+			},
+			param: "12",
+			query: "?kind=up",
+			want:  http.StatusOK,
+		},
+		{
+			name: "without kind",
+			s: &ServiceMock{
+				unreact: func(u user.User, id int64, kind string) error {
+					return nil
+				},
+			},
+			param: "12",
+			want:  http.StatusBadRequest,
+		},
+		{
+			name: "not affected",
+			s: &ServiceMock{
+				unreact: func(u user.User, id int64, kind string) error {
+					return service.ErrNotAffected
+				},
+			},
+			param: "12",
+			query: "?kind=down",
+			want:  http.StatusServiceUnavailable,
+		},
+		{
+			name: "unknown err",
+			s: &ServiceMock{
+				unreact: func(u user.User, id int64, kind string) error {
+					return errors.New("unknown")
+				},
+			},
+			param: "12",
+			query: "?kind=up",
+			want:  http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := "/endpoint/"
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.DELETE(url+":id/reactions", RemoveReaction(tt.s))
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(
+				http.MethodDelete, url+tt.param+"/reactions"+tt.query, nil,
+			)
+
+			router.ServeHTTP(rr, req)
+
+			if rr.Result().StatusCode != tt.want {
+				t.Errorf(
+					"handler RemoveReaction() = %v, want %v",
+					rr.Result().StatusCode, tt.want,
+				)
+			}
+		})
+	}
+}
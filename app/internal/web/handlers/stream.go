@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"CommentTree/internal/broadcast"
+	"CommentTree/internal/entities/event"
+	"CommentTree/internal/entities/request"
+	"CommentTree/internal/entities/response"
+
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+)
+
+const (
+	// Comment line which keeps idle connection alive.
+	HeartbeatInterval = 15 * time.Second
+)
+
+type streamer interface {
+	Subscribe(parentID, lastID int64) *broadcast.Subscription
+	Unsubscribe(s *broadcast.Subscription)
+}
+
+// Stream sends events about replies to parent as Server-Sent Events.
+func Stream(b streamer) ginext.HandlerFunc {
+	return func(ctx *ginext.Context) {
+		const op = "internal.web.handlers.Stream"
+
+		var req request.Stream
+		if err := ctx.BindQuery(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, response.Error(
+				"wrong query, data or types in query",
+			))
+			return
+		}
+
+		if msg := req.Validate(); msg != "" {
+			ctx.JSON(http.StatusBadRequest, response.Error(
+				msg,
+			))
+			return
+		}
+
+		// Browser sends header by itself on reconnect.
+		if h := ctx.GetHeader("Last-Event-ID"); h != "" {
+			id, err := strconv.ParseInt(h, 10, 64)
+			if err != nil || id < 0 {
+				ctx.JSON(http.StatusBadRequest, response.Error(
+					"wrong Last-Event-ID header",
+				))
+				return
+			}
+			req.LastEventID = id
+		}
+
+		// Stream lives longer than write timeout of server.
+		rc := http.NewResponseController(ctx.Writer)
+		if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+			zlog.Logger.Debug().Err(fmt.Errorf("%s: %w", op, err)).Send()
+		}
+
+		ctx.Header("Content-Type", "text/event-stream")
+		ctx.Header("Cache-Control", "no-cache")
+		ctx.Header("Connection", "keep-alive")
+		ctx.Status(http.StatusOK)
+
+		sub := b.Subscribe(req.ParentID, req.LastEventID)
+		defer b.Unsubscribe(sub)
+
+		for _, e := range sub.Replay {
+			if err := writeEvent(ctx, e); err != nil {
+				return
+			}
+		}
+		ctx.Writer.Flush()
+
+		heartbeat := time.NewTicker(HeartbeatInterval)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case <-ctx.Request.Context().Done():
+				return
+			case e, ok := <-sub.C:
+				if !ok {
+					return
+				}
+				if err := writeEvent(ctx, e); err != nil {
+					return
+				}
+			case <-heartbeat.C:
+				if _, err := fmt.Fprint(ctx.Writer, ": heartbeat\n\n"); err != nil {
+					return
+				}
+			}
+			ctx.Writer.Flush()
+		}
+	}
+}
+
+func writeEvent(ctx *ginext.Context, e event.Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(
+		ctx.Writer, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data,
+	)
+	return err
+}
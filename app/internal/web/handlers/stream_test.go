@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"CommentTree/internal/broadcast"
+	"CommentTree/internal/entities/event"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestStream(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		query  string
+		header string
+		want   int
+		// Part of body, stream is finished by broadcaster shutdown.
+		wantBody string
+	}{
+		{
+			name:     "replay after last event id",
+			query:    "?parent=1",
+			header:   "1",
+			want:     http.StatusOK,
+			wantBody: "id: 2\nevent: created\n",
+		},
+		{
+			name:     "replay by query",
+			query:    "?parent=1&last_event_id=1",
+			want:     http.StatusOK,
+			wantBody: "id: 2\nevent: created\n",
+		},
+		{
+			name:  "bad parent",
+			query: "?parent=-1",
+			want:  http.StatusBadRequest,
+		},
+		{
+			name:   "bad header",
+			query:  "?parent=1",
+			header: "abc",
+			want:   http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := "/endpoint"
+
+			b := broadcast.New(10)
+			b.Publish(event.Event{Type: event.TypeCreated, CommentID: 3, ParentID: 1})
+			b.Publish(event.Event{Type: event.TypeCreated, CommentID: 4, ParentID: 1})
+			// Closed broadcaster finishes stream after replay.
+			b.Shutdown()
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET(url, Stream(b))
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, url+tt.query, nil)
+			if tt.header != "" {
+				req.Header.Set("Last-Event-ID", tt.header)
+			}
+
+			router.ServeHTTP(rr, req)
+
+			if rr.Result().StatusCode != tt.want {
+				t.Fatalf(
+					"handler Stream() = %v, want %v",
+					rr.Result().StatusCode, tt.want,
+				)
+			}
+			if !strings.Contains(rr.Body.String(), tt.wantBody) {
+				t.Errorf("handler Stream() body = %q, want %q", rr.Body.String(), tt.wantBody)
+			}
+			if tt.wantBody != "" && strings.Contains(rr.Body.String(), "id: 1\n") {
+				t.Errorf("handler Stream() replayed already seen event")
+			}
+		})
+	}
+}
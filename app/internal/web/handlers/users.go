@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"CommentTree/internal/entities/request"
+	"CommentTree/internal/entities/response"
+	"CommentTree/internal/entities/user"
+	"CommentTree/internal/service"
+
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+)
+
+const (
+	// Context key of authenticated user.
+	UserKey = "user"
+)
+
+// CurrentUser returns user which was put to context by auth middleware.
+func CurrentUser(ctx *ginext.Context) (user.User, bool) {
+	v, ok := ctx.Get(UserKey)
+	if !ok {
+		return user.User{}, false
+	}
+
+	u, ok := v.(user.User)
+	return u, ok
+}
+
+// ClientKey identifies client for limits, it's user or IP for anonymous.
+func ClientKey(ctx *ginext.Context) string {
+	if u, ok := CurrentUser(ctx); ok {
+		return "user:" + strconv.FormatInt(u.ID, 10)
+	}
+
+	return "ip:" + ctx.ClientIP()
+}
+
+func Register(s servicer) ginext.HandlerFunc {
+	return func(ctx *ginext.Context) {
+		const op = "internal.web.handlers.Register"
+
+		ctx.Header("Content-Type", "application/json")
+
+		var req request.Register
+		if err := ctx.BindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, response.Error(
+				"wrong json, data or types in json",
+			))
+			return
+		}
+
+		if msg := req.Validate(); msg != "" {
+			ctx.JSON(http.StatusBadRequest, response.Error(
+				msg,
+			))
+			return
+		}
+
+		u, token, err := s.Register(req.Name)
+		if errors.Is(err, service.ErrAlreadyExists) {
+			ctx.JSON(http.StatusConflict, response.Error(
+				err.Error(),
+			))
+			return
+		} else if errors.Is(err, service.ErrWrongData) {
+			ctx.JSON(http.StatusServiceUnavailable, response.Error(
+				err.Error(),
+			))
+			return
+		} else if err != nil {
+			zlog.Logger.Error().Err(fmt.Errorf("%s: %w", op, err)).Send()
+			ctx.JSON(http.StatusInternalServerError, response.Error(
+				InternalError,
+			))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, response.Result(ginext.H{
+			"user":  u,
+			"token": token,
+		}))
+	}
+}
+
+func Me(ctx *ginext.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	u, ok := CurrentUser(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, response.Error(
+			"unauthorized",
+		))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.Result(u))
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"CommentTree/internal/entities/user"
+	"CommentTree/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRegister(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		s    servicer
+		body string
+		want int
+	}{
+		{
+			name: "good",
+			s: &ServiceMock{
+				regF: func(name string) (user.User, string, error) {
+					return user.User{ID: 1, Name: name}, "token", nil
+				},
+			},
+			body: `{"name": "bob"}`,
+			want: http.StatusOK,
+		},
+		{
+			name: "bad json",
+			s: &ServiceMock{
+				regF: func(name string) (user.User, string, error) {
+					return user.User{}, "", nil
+				},
+			},
+			body: `{"}`,
+			want: http.StatusBadRequest,
+		},
+		{
+			name: "empty name",
+			s: &ServiceMock{
+				regF: func(name string) (user.User, string, error) {
+					return user.User{}, "", nil
+				},
+			},
+			body: `{"name": ""}`,
+			want: http.StatusBadRequest,
+		},
+		{
+			name: "name is taken",
+			s: &ServiceMock{
+				regF: func(name string) (user.User, string, error) {
+					return user.User{}, "", service.ErrAlreadyExists
+				},
+			},
+			body: `{"name": "bob"}`,
+			want: http.StatusConflict,
+		},
+		{
+			name: "unknown err",
+			s: &ServiceMock{
+				regF: func(name string) (user.User, string, error) {
+					return user.User{}, "", errors.New("unknown")
+				},
+			},
+			body: `{"name": "bob"}`,
+			want: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := "/endpoint"
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.POST(url, Register(tt.s))
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(
+				http.MethodPost, url, bytes.NewReader([]byte(tt.body)),
+			)
+
+			router.ServeHTTP(rr, req)
+
+			if rr.Result().StatusCode != tt.want {
+				t.Errorf(
+					"handler Register() = %v, want %v",
+					rr.Result().StatusCode, tt.want,
+				)
+			}
+		})
+	}
+}
+
+func TestMe(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// User which was set by auth middleware.
+		u    *user.User
+		want int
+	}{
+		{
+			name: "good",
+			u:    &user.User{ID: 1, Name: "bob"},
+			want: http.StatusOK,
+		},
+		{
+			name: "anonymous",
+			want: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := "/endpoint"
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET(url, func(ctx *gin.Context) {
+				if tt.u != nil {
+					ctx.Set(UserKey, *tt.u)
+				}
+			}, Me)
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, url, nil)
+
+			router.ServeHTTP(rr, req)
+
+			if rr.Result().StatusCode != tt.want {
+				t.Errorf(
+					"handler Me() = %v, want %v",
+					rr.Result().StatusCode, tt.want,
+				)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"CommentTree/internal/entities/request"
+	"CommentTree/internal/entities/response"
+	"CommentTree/internal/entities/webhook"
+	"CommentTree/internal/service"
+
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+)
+
+func CreateWebhook(s servicer) ginext.HandlerFunc {
+	return func(ctx *ginext.Context) {
+		const op = "internal.web.handlers.CreateWebhook"
+
+		ctx.Header("Content-Type", "application/json")
+
+		var req request.CreateWebhook
+		if err := ctx.BindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, response.Error(
+				"wrong json, data or types in json",
+			))
+			return
+		}
+
+		if msg := req.Validate(); msg != "" {
+			ctx.JSON(http.StatusBadRequest, response.Error(
+				msg,
+			))
+			return
+		}
+
+		u, _ := CurrentUser(ctx)
+		w, err := s.CreateWebhook(u, webhook.Webhook{
+			URL:    req.URL,
+			Events: req.Events,
+			Secret: req.Secret,
+		})
+		if errors.Is(err, service.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, response.Error(
+				err.Error(),
+			))
+			return
+		} else if errors.Is(err, service.ErrWrongData) {
+			ctx.JSON(http.StatusServiceUnavailable, response.Error(
+				err.Error(),
+			))
+			return
+		} else if err != nil {
+			zlog.Logger.Error().Err(fmt.Errorf("%s: %w", op, err)).Send()
+			ctx.JSON(http.StatusInternalServerError, response.Error(
+				InternalError,
+			))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, response.Result(w))
+	}
+}
+
+func Webhooks(s servicer) ginext.HandlerFunc {
+	return func(ctx *ginext.Context) {
+		const op = "internal.web.handlers.Webhooks"
+
+		ctx.Header("Content-Type", "application/json")
+
+		u, _ := CurrentUser(ctx)
+		hooks, err := s.Webhooks(u)
+		if errors.Is(err, service.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, response.Error(
+				err.Error(),
+			))
+			return
+		} else if err != nil {
+			zlog.Logger.Error().Err(fmt.Errorf("%s: %w", op, err)).Send()
+			ctx.JSON(http.StatusInternalServerError, response.Error(
+				InternalError,
+			))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, response.Result(hooks))
+	}
+}
+
+func DeleteWebhook(s servicer) ginext.HandlerFunc {
+	return moderate(s.DeleteWebhook, "internal.web.handlers.DeleteWebhook")
+}
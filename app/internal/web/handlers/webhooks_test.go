@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"CommentTree/internal/entities/event"
+	"CommentTree/internal/entities/request"
+	"CommentTree/internal/entities/user"
+	"CommentTree/internal/entities/webhook"
+	"CommentTree/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCreateWebhook(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		s    servicer
+		body request.CreateWebhook
+		want int
+	}{
+		{
+			name: "good",
+			s: &ServiceMock{
+				hookF: func(u user.User, w webhook.Webhook) (webhook.Webhook, error) {
+					return w, nil
+				},
+			},
+			body: request.CreateWebhook{
+				URL:    "https://example.com/hook",
+				Events: []string{event.TypeCreated},
+			},
+			want: http.StatusOK,
+		},
+		{
+			name: "bad url",
+			s:    &ServiceMock{},
+			body: request.CreateWebhook{
+				URL:    "example.com",
+				Events: []string{event.TypeCreated},
+			},
+			want: http.StatusBadRequest,
+		},
+		{
+			name: "forbidden",
+			s: &ServiceMock{
+				hookF: func(u user.User, w webhook.Webhook) (webhook.Webhook, error) {
+					return webhook.Webhook{}, service.ErrForbidden
+				},
+			},
+			body: request.CreateWebhook{
+				URL:    "https://example.com/hook",
+				Events: []string{event.TypeDeleted},
+			},
+			want: http.StatusForbidden,
+		},
+		{
+			name: "unknown err",
+			s: &ServiceMock{
+				hookF: func(u user.User, w webhook.Webhook) (webhook.Webhook, error) {
+					return webhook.Webhook{}, errors.New("unknown")
+				},
+			},
+			body: request.CreateWebhook{
+				URL:    "https://example.com/hook",
+				Events: []string{event.TypeDeleted},
+			},
+			want: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := "/endpoint"
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.POST(url, CreateWebhook(tt.s))
+
+			body, err := json.Marshal(tt.body)
+			if err != nil {
+				t.Fatalf("json.Marshal() err = %v", err)
+			}
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+
+			router.ServeHTTP(rr, req)
+
+			if rr.Result().StatusCode != tt.want {
+				t.Errorf(
+					"handler CreateWebhook() = %v, want %v",
+					rr.Result().StatusCode, tt.want,
+				)
+			}
+		})
+	}
+}
+
+func TestWebhooks(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		s    servicer
+		want int
+	}{
+		{
+			name: "good",
+			s: &ServiceMock{
+				hooksF: func(u user.User) ([]webhook.Webhook, error) {
+					return []webhook.Webhook{}, nil
+				},
+			},
+			want: http.StatusOK,
+		},
+		{
+			name: "forbidden",
+			s: &ServiceMock{
+				hooksF: func(u user.User) ([]webhook.Webhook, error) {
+					return nil, service.ErrForbidden
+				},
+			},
+			want: http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := "/endpoint"
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET(url, Webhooks(tt.s))
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, url, nil)
+
+			router.ServeHTTP(rr, req)
+
+			if rr.Result().StatusCode != tt.want {
+				t.Errorf(
+					"handler Webhooks() = %v, want %v",
+					rr.Result().StatusCode, tt.want,
+				)
+			}
+		})
+	}
+}
@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"CommentTree/internal/broadcast"
+	"CommentTree/internal/entities/comment"
+	"CommentTree/internal/entities/request"
+	"CommentTree/internal/entities/response"
+	"CommentTree/internal/entities/user"
+	"CommentTree/internal/service"
+
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// Messages waiting for sending to client.
+	WSSendBuffer = 64
+	// Subscriptions of one connection.
+	MaxWSSubscriptions = 50
+	// Max size of message from client.
+	MaxWSMessageSize = 16 << 10
+)
+
+// Routes of REST api, their limits are applied to the same messages.
+var wsRoutes = map[string]string{
+	request.WSCreate: http.MethodPost + " /comments",
+	request.WSEdit:   http.MethodPatch + " /comments/:id",
+	request.WSDelete: http.MethodDelete + " /comments/:id",
+}
+
+type limiter interface {
+	Allow(route, client string) (time.Duration, bool)
+}
+
+// WebSocket serves connection where client subscribes to replies of
+// several parents and creates, edits and deletes comments through s.
+// Changes are limited by l like the same REST requests.
+func WebSocket(s servicer, b streamer, l limiter) ginext.HandlerFunc {
+	return func(ctx *ginext.Context) {
+		u, authorized := CurrentUser(ctx)
+		c := &wsConn{
+			s:          s,
+			b:          b,
+			l:          l,
+			u:          u,
+			authorized: authorized,
+			client:     ClientKey(ctx),
+			out:        make(chan response.WSMessage, WSSendBuffer),
+			done:       make(chan struct{}),
+			subs:       make(map[int64]*broadcast.Subscription),
+		}
+
+		server := websocket.Server{
+			Handshake: sameOrigin,
+			Handler:   c.serve,
+		}
+		server.ServeHTTP(ctx.Writer, ctx.Request)
+	}
+}
+
+// sameOrigin rejects browsers from other sites,
+// clients without Origin header are allowed.
+func sameOrigin(config *websocket.Config, req *http.Request) error {
+	origin, err := websocket.Origin(config, req)
+	if err != nil {
+		return err
+	}
+	if origin != nil && origin.Host != req.Host {
+		return errors.New("cross origin websocket")
+	}
+	config.Origin = origin
+
+	return nil
+}
+
+type wsConn struct {
+	s servicer
+	b streamer
+	l limiter
+	// User of upgrade request.
+	u          user.User
+	authorized bool
+	client     string
+
+	ws   *websocket.Conn
+	out  chan response.WSMessage
+	done chan struct{}
+	wg   sync.WaitGroup
+
+	mu   sync.Mutex
+	subs map[int64]*broadcast.Subscription
+}
+
+func (c *wsConn) serve(ws *websocket.Conn) {
+	c.ws = ws
+	ws.MaxPayloadBytes = MaxWSMessageSize
+	// Hijacked connection keeps timeouts of http server.
+	_ = ws.SetDeadline(time.Time{})
+
+	c.wg.Add(1)
+	go c.write()
+
+	defer func() {
+		close(c.done)
+		c.unsubscribeAll()
+		c.wg.Wait()
+	}()
+
+	for {
+		var data []byte
+		if err := websocket.Message.Receive(ws, &data); err != nil {
+			return
+		}
+		if !c.handle(data) {
+			return
+		}
+	}
+}
+
+func (c *wsConn) write() {
+	defer c.wg.Done()
+
+	for {
+		select {
+		case m := <-c.out:
+			if err := websocket.JSON.Send(c.ws, m); err != nil {
+				_ = c.ws.Close()
+				return
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// send queues m, it returns false when connection is closed.
+func (c *wsConn) send(m response.WSMessage) bool {
+	select {
+	case c.out <- m:
+		return true
+	case <-c.done:
+		return false
+	}
+}
+
+func (c *wsConn) reply(requestID string, r response.Response) bool {
+	return c.send(response.WSReplyTo(requestID, r))
+}
+
+func (c *wsConn) handle(data []byte) bool {
+	var m request.WSMessage
+	if err := json.Unmarshal(data, &m); err != nil {
+		return c.reply("", response.Error(
+			"wrong json, data or types in json",
+		))
+	}
+
+	if msg := m.Validate(); msg != "" {
+		return c.reply(m.RequestID, response.Error(
+			msg,
+		))
+	}
+
+	switch m.Type {
+	case request.WSSubscribe:
+		return c.subscribe(m)
+	case request.WSUnsubscribe:
+		c.unsubscribe(m.ParentID)
+		return c.reply(m.RequestID, response.OK())
+	}
+
+	return c.reply(m.RequestID, c.change(m))
+}
+
+func (c *wsConn) subscribe(m request.WSMessage) bool {
+	c.mu.Lock()
+	if _, ok := c.subs[m.ParentID]; ok {
+		c.mu.Unlock()
+		return c.reply(m.RequestID, response.Error(
+			"already subscribed to this parent",
+		))
+	}
+	if len(c.subs) >= MaxWSSubscriptions {
+		c.mu.Unlock()
+		return c.reply(m.RequestID, response.Error(
+			fmt.Sprintf("too many subscriptions, max is %d", MaxWSSubscriptions),
+		))
+	}
+	sub := c.b.Subscribe(m.ParentID, m.LastEventID)
+	c.subs[m.ParentID] = sub
+	c.mu.Unlock()
+
+	// Events go after reply.
+	if !c.reply(m.RequestID, response.OK()) {
+		return false
+	}
+
+	c.wg.Add(1)
+	go c.forward(sub)
+
+	return true
+}
+
+func (c *wsConn) forward(sub *broadcast.Subscription) {
+	defer c.wg.Done()
+
+	for _, e := range sub.Replay {
+		if !c.send(response.WSEventOf(e)) {
+			return
+		}
+	}
+	for e := range sub.C {
+		if !c.send(response.WSEventOf(e)) {
+			return
+		}
+	}
+
+	// Dropped by broadcaster, client reconnects and
+	// subscribes with last_event_id to get missed events.
+	c.mu.Lock()
+	dropped := c.subs[sub.ParentID] == sub
+	c.mu.Unlock()
+	if dropped {
+		_ = c.ws.Close()
+	}
+}
+
+func (c *wsConn) unsubscribe(parentID int64) {
+	c.mu.Lock()
+	sub, ok := c.subs[parentID]
+	delete(c.subs, parentID)
+	c.mu.Unlock()
+
+	if ok {
+		c.b.Unsubscribe(sub)
+	}
+}
+
+func (c *wsConn) unsubscribeAll() {
+	c.mu.Lock()
+	subs := c.subs
+	c.subs = make(map[int64]*broadcast.Subscription)
+	c.mu.Unlock()
+
+	for _, sub := range subs {
+		c.b.Unsubscribe(sub)
+	}
+}
+
+// change creates, edits or deletes comment by message of client.
+func (c *wsConn) change(m request.WSMessage) response.Response {
+	const op = "internal.web.handlers.WebSocket"
+
+	if wait, ok := c.l.Allow(wsRoutes[m.Type], c.client); !ok {
+		return response.Error(fmt.Sprintf(
+			"too many requests, retry after %d seconds",
+			int(math.Ceil(wait.Seconds())),
+		))
+	}
+
+	if m.Type != request.WSCreate && !c.authorized {
+		return response.Error("unauthorized")
+	}
+
+	var (
+		id  int64
+		err error
+	)
+	switch m.Type {
+	case request.WSCreate:
+		// Anonymous comment when there is no user.
+		id, err = c.s.CreateComment(comment.Comment{
+			Message:   m.Message,
+			ParentID:  m.ParentID,
+			AuthorID:  c.u.ID,
+			ThreadKey: m.ThreadKey,
+		})
+	case request.WSEdit:
+		err = c.s.EditComment(c.u, m.ID, m.Message)
+	case request.WSDelete:
+		err = c.s.DeleteComment(c.u, m.ID, m.Cascade)
+	}
+
+	if errors.Is(err, service.ErrRejected) || errors.Is(err, service.ErrWrongData) ||
+		errors.Is(err, service.ErrNotAffected) || errors.Is(err, service.ErrForbidden) {
+		return response.Error(err.Error())
+	} else if err != nil {
+		zlog.Logger.Error().Err(fmt.Errorf("%s: %w", op, err)).Send()
+		return response.Error(InternalError)
+	}
+
+	if m.Type == request.WSCreate {
+		return response.Result(id)
+	}
+
+	return response.OK()
+}
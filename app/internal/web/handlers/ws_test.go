@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"CommentTree/internal/broadcast"
+	"CommentTree/internal/entities/comment"
+	"CommentTree/internal/entities/event"
+	"CommentTree/internal/entities/request"
+	"CommentTree/internal/entities/response"
+	"CommentTree/internal/entities/user"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+type LimiterMock struct {
+	allow bool
+}
+
+func (lm *LimiterMock) Allow(route, client string) (time.Duration, bool) {
+	return time.Second, lm.allow
+}
+
+// dialWS starts server with WebSocket handler, with authorized
+// connection belongs to user 1.
+func dialWS(
+	t *testing.T, s servicer, b *broadcast.Broadcaster, allow, authorized bool,
+) *websocket.Conn {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	if authorized {
+		router.Use(func(ctx *gin.Context) {
+			ctx.Set(UserKey, user.User{ID: 1})
+		})
+	}
+	router.GET("/ws", WebSocket(s, b, &LimiterMock{allow: allow}))
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	ws, err := websocket.Dial(
+		"ws"+strings.TrimPrefix(server.URL, "http")+"/ws", "", server.URL,
+	)
+	if err != nil {
+		t.Fatalf("Dial() err = %v", err)
+	}
+	t.Cleanup(func() { _ = ws.Close() })
+
+	return ws
+}
+
+func exchange(t *testing.T, ws *websocket.Conn, m request.WSMessage) response.WSMessage {
+	t.Helper()
+
+	if err := websocket.JSON.Send(ws, m); err != nil {
+		t.Fatalf("Send() err = %v", err)
+	}
+
+	return receive(t, ws)
+}
+
+func receive(t *testing.T, ws *websocket.Conn) response.WSMessage {
+	t.Helper()
+
+	_ = ws.SetReadDeadline(time.Now().Add(time.Second))
+	var got response.WSMessage
+	if err := websocket.JSON.Receive(ws, &got); err != nil {
+		t.Fatalf("Receive() err = %v", err)
+	}
+
+	return got
+}
+
+func TestWebSocket_Subscribe(t *testing.T) {
+	b := broadcast.New(10)
+	b.Publish(event.Event{Type: event.TypeCreated, CommentID: 3, ParentID: 1})
+	ws := dialWS(t, &ServiceMock{}, b, true, false)
+
+	got := exchange(t, ws, request.WSMessage{
+		Type:      request.WSSubscribe,
+		RequestID: "a",
+		ParentID:  1,
+	})
+	if got.Type != response.WSReply || got.RequestID != "a" ||
+		got.Status != response.StatusOK {
+		t.Fatalf("subscribe reply = %v", got)
+	}
+
+	b.Publish(event.Event{Type: event.TypeDeleted, CommentID: 4, ParentID: 2})
+	b.Publish(event.Event{Type: event.TypeEdited, CommentID: 5, ParentID: 1})
+
+	// Event of other parent isn't sent, old event isn't replayed.
+	got = receive(t, ws)
+	if got.Type != response.WSEvent || got.Event == nil || got.Event.CommentID != 5 {
+		t.Fatalf("event = %v, want edit of 5", got)
+	}
+
+	got = exchange(t, ws, request.WSMessage{
+		Type:      request.WSSubscribe,
+		RequestID: "b",
+		ParentID:  1,
+	})
+	if got.Status != response.StatusError {
+		t.Errorf("second subscribe reply = %v, want error", got)
+	}
+}
+
+func TestWebSocket_Change(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		m          request.WSMessage
+		allow      bool
+		authorized bool
+		want       string
+	}{
+		{
+			name: "create",
+			m: request.WSMessage{
+				Type:     request.WSCreate,
+				Message:  "hi",
+				ParentID: 3,
+			},
+			allow: true,
+			want:  response.StatusOK,
+		},
+		{
+			name: "edit",
+			m: request.WSMessage{
+				Type:    request.WSEdit,
+				ID:      3,
+				Message: "hi",
+			},
+			allow:      true,
+			authorized: true,
+			want:       response.StatusOK,
+		},
+		{
+			name: "anonymous delete",
+			m: request.WSMessage{
+				Type: request.WSDelete,
+				ID:   3,
+			},
+			allow: true,
+			want:  response.StatusError,
+		},
+		{
+			name: "limited",
+			m: request.WSMessage{
+				Type:     request.WSCreate,
+				Message:  "hi",
+				ParentID: 3,
+			},
+			allow: false,
+			want:  response.StatusError,
+		},
+		{
+			name: "unknown type",
+			m: request.WSMessage{
+				Type: "drop",
+			},
+			allow: true,
+			want:  response.StatusError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &ServiceMock{
+				createF: func(c comment.Comment) (int64, error) {
+					return 7, nil
+				},
+				editF: func(u user.User, id int64, message string) error {
+					return nil
+				},
+				deleteF: func(u user.User, id int64, cascade bool) error {
+					return nil
+				},
+			}
+			ws := dialWS(t, s, broadcast.New(10), tt.allow, tt.authorized)
+
+			tt.m.RequestID = tt.name
+			got := exchange(t, ws, tt.m)
+			if got.RequestID != tt.name || got.Status != tt.want {
+				t.Errorf("reply = %v, want status %v", got, tt.want)
+			}
+		})
+	}
+}
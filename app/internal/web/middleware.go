@@ -0,0 +1,98 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"CommentTree/internal/entities/response"
+	"CommentTree/internal/entities/user"
+	"CommentTree/internal/service"
+	"CommentTree/internal/web/handlers"
+
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+)
+
+const (
+	BearerPrefix = "Bearer "
+)
+
+type authenticator interface {
+	Authenticate(token string) (user.User, error)
+}
+
+// Auth puts owner of bearer token to context,
+// requests without token pass as anonymous.
+func Auth(a authenticator) ginext.HandlerFunc {
+	return func(ctx *ginext.Context) {
+		const op = "internal.web.Auth"
+
+		header := ctx.GetHeader("Authorization")
+		if header == "" {
+			ctx.Next()
+			return
+		}
+
+		token, ok := strings.CutPrefix(header, BearerPrefix)
+		if !ok {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response.Error(
+				"wrong authorization header, should be 'Bearer <token>'",
+			))
+			return
+		}
+
+		u, err := a.Authenticate(token)
+		if errors.Is(err, service.ErrUnauthorized) {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response.Error(
+				err.Error(),
+			))
+			return
+		} else if err != nil {
+			zlog.Logger.Error().Err(fmt.Errorf("%s: %w", op, err)).Send()
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(
+				handlers.InternalError,
+			))
+			return
+		}
+
+		ctx.Set(handlers.UserKey, u)
+		ctx.Next()
+	}
+}
+
+// RequireModerator aborts requests of not moderators, should go after Auth.
+func RequireModerator() ginext.HandlerFunc {
+	return func(ctx *ginext.Context) {
+		u, ok := handlers.CurrentUser(ctx)
+		if !ok {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response.Error(
+				"unauthorized",
+			))
+			return
+		}
+		if !u.IsModerator() {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, response.Error(
+				"only for moderators",
+			))
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// RequireAuth aborts requests without authenticated user, should go after Auth.
+func RequireAuth() ginext.HandlerFunc {
+	return func(ctx *ginext.Context) {
+		if _, ok := handlers.CurrentUser(ctx); !ok {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response.Error(
+				"unauthorized",
+			))
+			return
+		}
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,180 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"CommentTree/internal/entities/user"
+	"CommentTree/internal/service"
+	"CommentTree/internal/web/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuthMock struct {
+	authF func(token string) (user.User, error)
+}
+
+func (am *AuthMock) Authenticate(token string) (user.User, error) {
+	return am.authF(token)
+}
+
+func TestAuth(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		a       authenticator
+		header  string
+		require bool
+		want    int
+	}{
+		{
+			name: "good",
+			a: &AuthMock{
+				authF: func(token string) (user.User, error) {
+					return user.User{ID: 1}, nil
+				},
+			},
+			header:  "Bearer token",
+			require: true,
+			want:    http.StatusOK,
+		},
+		{
+			name: "anonymous",
+			a: &AuthMock{
+				authF: func(token string) (user.User, error) {
+					return user.User{}, errors.New("shouldn't be called")
+				},
+			},
+			want: http.StatusOK,
+		},
+		{
+			name: "anonymous on required",
+			a: &AuthMock{
+				authF: func(token string) (user.User, error) {
+					return user.User{}, errors.New("shouldn't be called")
+				},
+			},
+			require: true,
+			want:    http.StatusUnauthorized,
+		},
+		{
+			name: "wrong header",
+			a: &AuthMock{
+				authF: func(token string) (user.User, error) {
+					return user.User{ID: 1}, nil
+				},
+			},
+			header: "Basic token",
+			want:   http.StatusUnauthorized,
+		},
+		{
+			name: "wrong token",
+			a: &AuthMock{
+				authF: func(token string) (user.User, error) {
+					return user.User{}, service.ErrUnauthorized
+				},
+			},
+			header: "Bearer token",
+			want:   http.StatusUnauthorized,
+		},
+		{
+			name: "unknown err",
+			a: &AuthMock{
+				authF: func(token string) (user.User, error) {
+					return user.User{}, errors.New("unknown")
+				},
+			},
+			header: "Bearer token",
+			want:   http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := "/endpoint"
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.Use(Auth(tt.a))
+			if tt.require {
+				router.Use(RequireAuth())
+			}
+			router.GET(url, func(ctx *gin.Context) {
+				if _, ok := handlers.CurrentUser(ctx); tt.header != "" && !ok {
+					t.Error("user not set to context")
+				}
+				ctx.Status(http.StatusOK)
+			})
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, url, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+
+			router.ServeHTTP(rr, req)
+
+			if rr.Result().StatusCode != tt.want {
+				t.Errorf(
+					"middleware Auth() = %v, want %v",
+					rr.Result().StatusCode, tt.want,
+				)
+			}
+		})
+	}
+}
+
+func TestRequireModerator(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		u    *user.User
+		want int
+	}{
+		{
+			name: "moderator",
+			u:    &user.User{ID: 1, Role: user.RoleModerator},
+			want: http.StatusOK,
+		},
+		{
+			name: "user",
+			u:    &user.User{ID: 2, Role: user.RoleUser},
+			want: http.StatusForbidden,
+		},
+		{
+			name: "anonymous",
+			want: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := "/endpoint"
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.Use(func(ctx *gin.Context) {
+				if tt.u != nil {
+					ctx.Set(handlers.UserKey, *tt.u)
+				}
+			})
+			router.Use(RequireModerator())
+			router.GET(url, func(ctx *gin.Context) {
+				ctx.Status(http.StatusOK)
+			})
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, url, nil)
+
+			router.ServeHTTP(rr, req)
+
+			if rr.Result().StatusCode != tt.want {
+				t.Errorf(
+					"middleware RequireModerator() = %v, want %v",
+					rr.Result().StatusCode, tt.want,
+				)
+			}
+		})
+	}
+}
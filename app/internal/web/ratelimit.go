@@ -0,0 +1,141 @@
+package web
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"CommentTree/internal/entities/response"
+
+	"github.com/wb-go/wbf/ginext"
+)
+
+const (
+	// Buckets count after which full buckets are removed.
+	MaxBuckets = 10000
+)
+
+// RouteLimit sets token bucket for route like "POST /comments",
+// route is method and path pattern of router.
+type RouteLimit struct {
+	Route string `mapstructure:"route"`
+	// Tokens added per second.
+	Rate float64 `mapstructure:"rate"`
+	// Max tokens in bucket, it's count of requests in a row.
+	Burst int `mapstructure:"burst"`
+}
+
+type bucket struct {
+	limit  RouteLimit
+	tokens float64
+	last   time.Time
+}
+
+// refill returns tokens in bucket at now.
+func (b *bucket) refill(now time.Time) float64 {
+	return math.Min(
+		float64(b.limit.Burst),
+		b.tokens+now.Sub(b.last).Seconds()*b.limit.Rate,
+	)
+}
+
+// Limiter keeps token bucket for every client on every limited route.
+type Limiter struct {
+	mu      sync.Mutex
+	limits  map[string]RouteLimit
+	buckets map[string]*bucket
+	now     func() time.Time
+}
+
+// NewLimiter checks limits, limit with rate must have burst,
+// or it's bucket would never have a token.
+func NewLimiter(limits []RouteLimit) (*Limiter, error) {
+	l := &Limiter{
+		limits:  make(map[string]RouteLimit, len(limits)),
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+	for _, rl := range limits {
+		if rl.Rate > 0 && rl.Burst <= 0 {
+			return nil, fmt.Errorf("burst of limited route %q must be positive", rl.Route)
+		}
+		l.limits[rl.Route] = rl
+	}
+
+	return l, nil
+}
+
+// Allow spends token of client on route, when bucket is empty
+// it returns time until next token. Not limited routes and nil Limiter
+// always allow.
+func (l *Limiter) Allow(route, client string) (time.Duration, bool) {
+	if l == nil {
+		return 0, true
+	}
+
+	rl, ok := l.limits[route]
+	if !ok || rl.Rate <= 0 {
+		return 0, true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if len(l.buckets) > MaxBuckets {
+		l.sweep(now)
+	}
+
+	key := route + " " + client
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{limit: rl, tokens: float64(rl.Burst), last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = b.refill(now)
+	b.last = now
+
+	if b.tokens < 1 {
+		wait := (1 - b.tokens) / rl.Rate
+		return time.Duration(wait * float64(time.Second)), false
+	}
+	b.tokens--
+
+	return 0, true
+}
+
+// sweep removes buckets which are already refilled,
+// new bucket for the same client will be the same.
+func (l *Limiter) sweep(now time.Time) {
+	for key, b := range l.buckets {
+		if b.refill(now) >= float64(b.limit.Burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// RateLimit answers 429 when client exceeds limit of route, client
+// is IP, so it goes before Auth and doesn't wait for token lookup.
+// IP is taken from headers of trusted proxies only, see SetTrustedProxies
+// of router.
+func RateLimit(l *Limiter) ginext.HandlerFunc {
+	return func(ctx *ginext.Context) {
+		wait, ok := l.Allow(
+			ctx.Request.Method+" "+ctx.FullPath(), "ip:"+ctx.ClientIP(),
+		)
+		if !ok {
+			seconds := int(math.Ceil(wait.Seconds()))
+			ctx.Header("Retry-After", strconv.Itoa(seconds))
+			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, response.Error(
+				fmt.Sprintf("too many requests, retry after %d seconds", seconds),
+			))
+			return
+		}
+
+		ctx.Next()
+	}
+}
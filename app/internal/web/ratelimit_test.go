@@ -0,0 +1,159 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestNewLimiter(t *testing.T) {
+	tests := []struct {
+		name    string // description of this test case
+		limits  []RouteLimit
+		wantErr bool
+	}{
+		{
+			name:   "limited route",
+			limits: []RouteLimit{{Route: "POST /comments", Rate: 1, Burst: 2}},
+		},
+		{
+			name:   "off route without burst",
+			limits: []RouteLimit{{Route: "POST /comments"}},
+		},
+		{
+			name:    "rate without burst",
+			limits:  []RouteLimit{{Route: "POST /comments", Rate: 1}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewLimiter(tt.limits)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewLimiter() err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	l, err := NewLimiter([]RouteLimit{
+		{Route: "POST /comments", Rate: 1, Burst: 2},
+	})
+	if err != nil {
+		t.Fatalf("NewLimiter() err = %v", err)
+	}
+	l.now = func() time.Time { return now }
+
+	steps := []struct {
+		name    string // description of this step
+		route   string
+		client  string
+		advance time.Duration
+		want    bool
+	}{
+		{name: "first in burst", route: "POST /comments", client: "a", want: true},
+		{name: "second in burst", route: "POST /comments", client: "a", want: true},
+		{name: "empty bucket", route: "POST /comments", client: "a", want: false},
+		{name: "other client", route: "POST /comments", client: "b", want: true},
+		{name: "not limited route", route: "GET /comments", client: "a", want: true},
+		{
+			name: "refilled", route: "POST /comments", client: "a",
+			advance: time.Second, want: true,
+		},
+	}
+	for _, st := range steps {
+		now = now.Add(st.advance)
+		wait, got := l.Allow(st.route, st.client)
+		if got != st.want {
+			t.Fatalf("%s: Allow() = %v, want %v", st.name, got, st.want)
+		}
+		if !got && wait <= 0 {
+			t.Errorf("%s: Allow() wait = %v, want > 0", st.name, wait)
+		}
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		ip string
+		// Every request has new X-Forwarded-For.
+		forwarded bool
+		// Every request comes from new IP.
+		rotate bool
+		want   []int
+	}{
+		{
+			name: "by ip",
+			ip:   "10.0.0.1:1234",
+			want: []int{http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:      "spoofed forwarded for",
+			ip:        "10.0.0.1:1234",
+			forwarded: true,
+			want:      []int{http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:   "different ips",
+			rotate: true,
+			want:   []int{http.StatusOK, http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := "/endpoint"
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			// Like in main without trusted proxies in config.
+			if err := router.SetTrustedProxies(nil); err != nil {
+				t.Fatalf("SetTrustedProxies() err = %v", err)
+			}
+			l, err := NewLimiter([]RouteLimit{
+				{Route: http.MethodPost + " " + url, Rate: 0.1, Burst: 1},
+			})
+			if err != nil {
+				t.Fatalf("NewLimiter() err = %v", err)
+			}
+			router.Use(RateLimit(l))
+			router.POST(url, func(ctx *gin.Context) {
+				ctx.Status(http.StatusOK)
+			})
+
+			for i, want := range tt.want {
+				rr := httptest.NewRecorder()
+				req := httptest.NewRequest(http.MethodPost, url, nil)
+				req.RemoteAddr = tt.ip
+				if tt.forwarded {
+					req.Header.Set("X-Forwarded-For", "192.168.0."+string(rune('1'+i)))
+				}
+				if tt.rotate {
+					req.RemoteAddr = "10.0.0." + string(rune('1'+i)) + ":1234"
+				}
+
+				router.ServeHTTP(rr, req)
+
+				if rr.Result().StatusCode != want {
+					t.Fatalf(
+						"middleware RateLimit() = %v, want %v",
+						rr.Result().StatusCode, want,
+					)
+				}
+				if want == http.StatusTooManyRequests &&
+					rr.Result().Header.Get("Retry-After") != "10" {
+					t.Errorf(
+						"Retry-After = %q, want 10",
+						rr.Result().Header.Get("Retry-After"),
+					)
+				}
+			}
+		})
+	}
+}
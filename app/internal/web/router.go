@@ -1,14 +1,25 @@
 package web
 
 import (
+	"CommentTree/internal/broadcast"
 	"CommentTree/internal/service"
 	"CommentTree/internal/web/handlers"
 
 	"github.com/wb-go/wbf/ginext"
 )
 
-func Routes(router *ginext.Engine, s *service.Service) {
+// Routes registers all routes, l limits routes from it's config,
+// without l requests aren't limited. Live updates are taken from b.
+func Routes(
+	router *ginext.Engine, s *service.Service,
+	l *Limiter, b *broadcast.Broadcaster,
+) {
 	router.Delims("__", "__").LoadHTMLGlob("templates/*.html")
+	// Limits go before Auth, so floods of bad tokens don't reach storage.
+	if l != nil {
+		router.Use(RateLimit(l))
+	}
+	router.Use(Auth(s))
 
 	// html
 	router.GET("/", handlers.MainPage)
@@ -17,7 +28,30 @@ func Routes(router *ginext.Engine, s *service.Service) {
 	router.GET("/show", handlers.CommentsPage)
 
 	// api
+	router.POST("/users", handlers.Register(s))
+	router.GET("/users/me", RequireAuth(), handlers.Me)
+
 	router.POST("/comments", handlers.CreateComment(s))
-	router.DELETE("/comments/:id", handlers.DeleteComment(s))
+	router.PATCH("/comments/:id", RequireAuth(), handlers.EditComment(s))
+	router.DELETE("/comments/:id", RequireAuth(), handlers.DeleteComment(s))
+	router.POST("/comments/:id/restore", RequireAuth(), handlers.RestoreComment(s))
+	router.POST("/comments/:id/reactions", RequireAuth(), handlers.AddReaction(s))
+	router.DELETE("/comments/:id/reactions", RequireAuth(), handlers.RemoveReaction(s))
 	router.GET("/comments", handlers.Comments(s))
+	router.GET("/comments/stream", handlers.Stream(b))
+	router.GET("/ws", handlers.WebSocket(s, b, l))
+	router.GET("/comments/:id", handlers.Comment(s))
+	router.GET("/comments/:id/tree", handlers.Tree(s))
+	router.GET("/comments/:id/path", handlers.Path(s))
+	router.GET("/comments/:id/revisions", handlers.Revisions(s))
+
+	moderation := router.Group("/moderation", RequireModerator())
+	moderation.GET("/queue", handlers.ModerationQueue(s))
+	moderation.POST("/comments/:id/approve", handlers.ApproveComment(s))
+	moderation.POST("/comments/:id/reject", handlers.RejectComment(s))
+
+	webhooks := router.Group("/webhooks", RequireModerator())
+	webhooks.POST("", handlers.CreateWebhook(s))
+	webhooks.GET("", handlers.Webhooks(s))
+	webhooks.DELETE("/:id", handlers.DeleteWebhook(s))
 }
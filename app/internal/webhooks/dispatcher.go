@@ -0,0 +1,210 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"CommentTree/internal/entities/webhook"
+
+	"github.com/wb-go/wbf/retry"
+	"github.com/wb-go/wbf/zlog"
+)
+
+const (
+	// Defaults for zero fields of Config.
+	DefaultPollInterval = 5 * time.Second
+	DefaultBatch        = 50
+	DefaultMaxAttempts  = 10
+	DefaultTimeout      = 10 * time.Second
+
+	// Max wait of delivery between tries in outbox.
+	MaxBackoff = time.Hour
+	// Part of response body which is read to reuse connection.
+	MaxResponseBody = 64 << 10
+)
+
+var (
+	DefaultRetry = retry.Strategy{
+		Attempts: 3,
+		Delay:    time.Second,
+		Backoff:  2,
+	}
+)
+
+type store interface {
+	ClaimDeliveries(limit int, lease time.Duration, maxAttempts int) ([]webhook.Delivery, error)
+	CompleteDelivery(id int64) error
+	FailDelivery(id int64, reason string, retryAt time.Time) error
+}
+
+type Config struct {
+	// Outbox is checked with this interval.
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+	// Deliveries taken from outbox at once.
+	Batch int `mapstructure:"batch"`
+	// Tries of delivery, after them it stays in outbox unsent.
+	MaxAttempts int `mapstructure:"max_attempts"`
+	// Timeout of one request.
+	Timeout time.Duration `mapstructure:"timeout"`
+	// Requests of one try, tries are separated by growing backoff.
+	Retry retry.Strategy `mapstructure:"retry"`
+}
+
+// Dispatcher sends deliveries from outbox to webhooks,
+// several dispatchers can share one outbox.
+type Dispatcher struct {
+	str    store
+	cfg    Config
+	client *http.Client
+}
+
+func New(str store, cfg Config) *Dispatcher {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = DefaultPollInterval
+	}
+	if cfg.Batch <= 0 {
+		cfg.Batch = DefaultBatch
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = DefaultMaxAttempts
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	if cfg.Retry.Attempts <= 0 {
+		cfg.Retry = DefaultRetry
+	}
+
+	return &Dispatcher{
+		str:    str,
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+// Sign returns value of signature header for body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return webhook.SignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Run sends deliveries until ctx is done.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		d.dispatch(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// dispatch sends due deliveries until there are no more of them.
+func (d *Dispatcher) dispatch(ctx context.Context) {
+	const op = "internal.webhooks.dispatch"
+
+	for ctx.Err() == nil {
+		deliveries, err := d.str.ClaimDeliveries(
+			d.cfg.Batch, d.lease(), d.cfg.MaxAttempts,
+		)
+		if err != nil {
+			zlog.Logger.Error().Err(fmt.Errorf("%s: %w", op, err)).Send()
+			return
+		}
+
+		for _, del := range deliveries {
+			d.deliver(ctx, del)
+		}
+
+		if len(deliveries) < d.cfg.Batch {
+			return
+		}
+	}
+}
+
+// lease is time of sending the whole batch in the worst case,
+// other dispatchers don't take it meanwhile.
+func (d *Dispatcher) lease() time.Duration {
+	try := time.Duration(0)
+	delay := d.cfg.Retry.Delay
+	for i := 0; i < d.cfg.Retry.Attempts; i++ {
+		try += d.cfg.Timeout + delay
+		delay = time.Duration(float64(delay) * d.cfg.Retry.Backoff)
+	}
+
+	return try * time.Duration(d.cfg.Batch)
+}
+
+// backoff returns wait after failed try, it doubles with every try.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	wait := d.cfg.PollInterval
+	for i := 1; i < attempts && wait < MaxBackoff; i++ {
+		wait *= 2
+	}
+
+	return min(wait, MaxBackoff)
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, del webhook.Delivery) {
+	const op = "internal.webhooks.deliver"
+
+	err := retry.Do(func() error {
+		return d.send(ctx, del)
+	}, d.cfg.Retry)
+	if err == nil {
+		err = d.str.CompleteDelivery(del.ID)
+	} else {
+		err = d.str.FailDelivery(
+			del.ID, err.Error(), time.Now().Add(d.backoff(del.Attempts)),
+		)
+	}
+
+	if err != nil {
+		zlog.Logger.Error().Err(fmt.Errorf("%s: %w", op, err)).Send()
+	}
+}
+
+func (d *Dispatcher) send(ctx context.Context, del webhook.Delivery) error {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, del.URL, bytes.NewReader(del.Payload),
+	)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhook.HeaderEvent, del.Event)
+	req.Header.Set(webhook.HeaderDelivery, strconv.FormatInt(del.ID, 10))
+	req.Header.Set(webhook.HeaderSignature, Sign(del.Secret, del.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, MaxResponseBody))
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook answered with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
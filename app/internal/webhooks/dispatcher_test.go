@@ -0,0 +1,121 @@
+package webhooks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"CommentTree/internal/entities/webhook"
+
+	"github.com/wb-go/wbf/retry"
+)
+
+type StoreMock struct {
+	deliveries []webhook.Delivery
+	completed  []int64
+	failed     []int64
+}
+
+func (sm *StoreMock) ClaimDeliveries(limit int, lease time.Duration, maxAttempts int) ([]webhook.Delivery, error) {
+	d := sm.deliveries
+	sm.deliveries = nil
+	return d, nil
+}
+
+func (sm *StoreMock) CompleteDelivery(id int64) error {
+	sm.completed = append(sm.completed, id)
+	return nil
+}
+
+func (sm *StoreMock) FailDelivery(id int64, reason string, retryAt time.Time) error {
+	sm.failed = append(sm.failed, id)
+	return nil
+}
+
+func TestDispatcher_Dispatch(t *testing.T) {
+	const secret = "0123456789abcdef"
+
+	tests := []struct {
+		name string // description of this test case
+		// Status of webhook server.
+		status int
+
+		wantCompleted int
+		wantFailed    int
+	}{
+		{
+			name:          "delivered",
+			status:        http.StatusOK,
+			wantCompleted: 1,
+		},
+		{
+			name:       "failed",
+			status:     http.StatusInternalServerError,
+			wantFailed: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					requests++
+					body, _ := io.ReadAll(r.Body)
+					if r.Header.Get(webhook.HeaderSignature) != Sign(secret, body) {
+						t.Errorf("signature = %v, want %v",
+							r.Header.Get(webhook.HeaderSignature), Sign(secret, body))
+					}
+					if r.Header.Get(webhook.HeaderDelivery) != "7" {
+						t.Errorf("delivery = %v, want 7", r.Header.Get(webhook.HeaderDelivery))
+					}
+					w.WriteHeader(tt.status)
+				},
+			))
+			defer server.Close()
+
+			str := &StoreMock{
+				deliveries: []webhook.Delivery{{
+					ID:       7,
+					URL:      server.URL,
+					Secret:   secret,
+					Event:    "created",
+					Payload:  []byte(`{"event":"created","comment_id":3}`),
+					Attempts: 1,
+				}},
+			}
+			d := New(str, Config{
+				Retry: retry.Strategy{Attempts: 2, Delay: time.Millisecond, Backoff: 1},
+			})
+
+			d.dispatch(context.Background())
+
+			if len(str.completed) != tt.wantCompleted || len(str.failed) != tt.wantFailed {
+				t.Errorf(
+					"dispatch() completed = %v, failed = %v",
+					str.completed, str.failed,
+				)
+			}
+			// Failed try is repeated by retry strategy.
+			if tt.wantFailed > 0 && requests != 2 {
+				t.Errorf("dispatch() requests = %v, want 2", requests)
+			}
+		})
+	}
+}
+
+func TestDispatcher_Backoff(t *testing.T) {
+	d := New(&StoreMock{}, Config{PollInterval: time.Second})
+
+	if got := d.backoff(1); got != time.Second {
+		t.Errorf("backoff(1) = %v, want 1s", got)
+	}
+	if got := d.backoff(4); got != 8*time.Second {
+		t.Errorf("backoff(4) = %v, want 8s", got)
+	}
+	if got := d.backoff(100); got != MaxBackoff {
+		t.Errorf("backoff(100) = %v, want %v", got, MaxBackoff)
+	}
+}
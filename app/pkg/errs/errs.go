@@ -10,4 +10,6 @@ var (
 	ErrDBNotFound = errors.New("not found")
 	// ErrDBViolatesForeignKey when foreign key is wrong.
 	ErrDBViolatesForeignKey = errors.New("violates foreign key")
+	// ErrDBAlreadyExists when unique field is already taken.
+	ErrDBAlreadyExists = errors.New("already exists")
 )